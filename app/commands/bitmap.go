@@ -0,0 +1,545 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrBitOffset = errors.New("bit offset is not an integer or out of range")
+	ErrBitValue  = errors.New("bit is not an integer or out of range")
+)
+
+// maxBitOffset is the highest addressable bit of a string of maxStringLength bytes.
+const maxBitOffset = maxStringLength*8 - 1
+
+func parseBitOffset(arg string) (int, error) {
+	offset, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || offset < 0 || offset > maxBitOffset {
+		return 0, ErrBitOffset
+	}
+	return int(offset), nil
+}
+
+// getBit reads the bit at offset, counting from the most significant bit of the first byte.
+func getBit(buf []byte, offset int) int {
+	byteIndex := offset >> 3
+	if byteIndex >= len(buf) {
+		return 0
+	}
+	return int(buf[byteIndex]>>(7-uint(offset&7))) & 1
+}
+
+func setBit(buf []byte, offset int, value int) {
+	byteIndex := offset >> 3
+	mask := byte(1 << (7 - uint(offset&7)))
+	if value == 1 {
+		buf[byteIndex] |= mask
+	} else {
+		buf[byteIndex] &^= mask
+	}
+}
+
+// growBytes zero pads buf so that it holds at least size bytes.
+func growBytes(buf []byte, size int) []byte {
+	if size <= len(buf) {
+		return buf
+	}
+	return append(buf, make([]byte, size-len(buf))...)
+}
+
+type SETBITCommand struct {
+	baseCommand
+}
+
+func (c *SETBITCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'SETBIT' command")
+	}
+	key := args[1]
+	offset, err := parseBitOffset(args[2])
+	if err != nil {
+		return "", err
+	}
+	if args[3] != "0" && args[3] != "1" {
+		return "", ErrBitValue
+	}
+	value := int(args[3][0] - '0')
+
+	current, _, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	buf := growBytes([]byte(current), offset>>3+1)
+	previous := getBit(buf, offset)
+	setBit(buf, offset, value)
+	setStringKeepTTL(c.db, key, string(buf))
+	return previous, nil
+}
+
+type GETBITCommand struct {
+	baseCommand
+}
+
+func (c *GETBITCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'GETBIT' command")
+	}
+	offset, err := parseBitOffset(args[2])
+	if err != nil {
+		return "", err
+	}
+	current, _, err := getString(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	return getBit([]byte(current), offset), nil
+}
+
+// parseBitRange resolves a BITCOUNT/BITPOS start and end, given in BYTE or BIT
+// units, into an inclusive range of bit offsets. ok is false when the range is empty.
+func parseBitRange(length int, startArg string, endArg string, unit string) (start int, end int, ok bool, err error) {
+	start, err = strconv.Atoi(startArg)
+	if err != nil {
+		return 0, 0, false, ErrNotInteger
+	}
+	end, err = strconv.Atoi(endArg)
+	if err != nil {
+		return 0, 0, false, ErrNotInteger
+	}
+
+	isBit := false
+	switch strings.ToUpper(unit) {
+	case "", "BYTE":
+	case "BIT":
+		isBit = true
+		length = length * 8
+	default:
+		return 0, 0, false, ErrSyntax
+	}
+
+	if start < 0 {
+		start = length + start
+	}
+	if end < 0 {
+		end = length + end
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end = 0
+	}
+	if end >= length {
+		end = length - 1
+	}
+	if length == 0 || start > end {
+		return 0, 0, false, nil
+	}
+	if isBit {
+		return start, end, true, nil
+	}
+	return start * 8, end*8 + 7, true, nil
+}
+
+// countBits counts the set bits between the inclusive bit offsets start and end.
+func countBits(buf []byte, start int, end int) int {
+	count := 0
+	for start <= end && start&7 != 0 {
+		count += getBit(buf, start)
+		start++
+	}
+	for start+7 <= end {
+		count += bits.OnesCount8(buf[start>>3])
+		start += 8
+	}
+	for start <= end {
+		count += getBit(buf, start)
+		start++
+	}
+	return count
+}
+
+type BITCOUNTCommand struct {
+	baseCommand
+}
+
+func (c *BITCOUNTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 || len(args) > 5 {
+		return "", fmt.Errorf("wrong number of arguments for 'BITCOUNT' command")
+	}
+	if len(args) == 3 {
+		return "", ErrSyntax
+	}
+	current, _, err := getString(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	buf := []byte(current)
+
+	start, end := 0, len(buf)*8-1
+	if len(args) >= 4 {
+		unit := ""
+		if len(args) == 5 {
+			unit = args[4]
+		}
+		var ok bool
+		start, end, ok, err = parseBitRange(len(buf), args[2], args[3], unit)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return 0, nil
+		}
+	}
+	return countBits(buf, start, end), nil
+}
+
+type BITPOSCommand struct {
+	baseCommand
+}
+
+func (c *BITPOSCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 || len(args) > 6 {
+		return "", fmt.Errorf("wrong number of arguments for 'BITPOS' command")
+	}
+	if args[2] != "0" && args[2] != "1" {
+		return "", fmt.Errorf("The bit argument must be 1 or 0.")
+	}
+	bit := int(args[2][0] - '0')
+
+	current, ok, err := getString(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if bit == 1 {
+			return -1, nil
+		}
+		return 0, nil
+	}
+	buf := []byte(current)
+
+	startArg, endArg, unit := "0", "-1", ""
+	endGiven := false
+	if len(args) >= 4 {
+		startArg = args[3]
+	}
+	if len(args) >= 5 {
+		endArg = args[4]
+		endGiven = true
+	}
+	if len(args) == 6 {
+		unit = args[5]
+	}
+	start, end, ok, err := parseBitRange(len(buf), startArg, endArg, unit)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return -1, nil
+	}
+
+	for offset := start; offset <= end; offset++ {
+		if getBit(buf, offset) == bit {
+			return offset, nil
+		}
+	}
+	// without an explicit end the string is treated as padded with zeros on the right
+	if bit == 0 && !endGiven {
+		return end + 1, nil
+	}
+	return -1, nil
+}
+
+type BITOPCommand struct {
+	baseCommand
+}
+
+func (c *BITOPCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'BITOP' command")
+	}
+	operation := strings.ToUpper(args[1])
+	destination := args[2]
+	sourceKeys := args[3:]
+
+	switch operation {
+	case "AND", "OR", "XOR":
+	case "NOT":
+		if len(sourceKeys) != 1 {
+			return "", fmt.Errorf("BITOP NOT must be called with a single source key.")
+		}
+	default:
+		return "", ErrSyntax
+	}
+
+	sources := make([][]byte, 0, len(sourceKeys))
+	maxLength := 0
+	for _, key := range sourceKeys {
+		current, _, err := getString(c.db, key)
+		if err != nil {
+			return "", err
+		}
+		sources = append(sources, []byte(current))
+		maxLength = max(maxLength, len(current))
+	}
+
+	// shorter sources behave as if they were padded with zero bytes
+	result := make([]byte, maxLength)
+	for i := range result {
+		var value byte
+		for j, source := range sources {
+			var b byte
+			if i < len(source) {
+				b = source[i]
+			}
+			if j == 0 {
+				value = b
+				continue
+			}
+			switch operation {
+			case "AND":
+				value &= b
+			case "OR":
+				value |= b
+			case "XOR":
+				value ^= b
+			}
+		}
+		if operation == "NOT" {
+			value = ^value
+		}
+		result[i] = value
+	}
+
+	if len(result) == 0 {
+		c.db.DelValue(destination)
+		return 0, nil
+	}
+	c.db.SetValue(destination, string(result))
+	return len(result), nil
+}
+
+type bitfieldType struct {
+	signed bool
+	bits   int
+}
+
+func parseBitfieldType(arg string) (bitfieldType, error) {
+	invalid := fmt.Errorf("Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+	if len(arg) < 2 {
+		return bitfieldType{}, invalid
+	}
+	t := bitfieldType{}
+	switch arg[0] {
+	case 'i', 'I':
+		t.signed = true
+	case 'u', 'U':
+	default:
+		return bitfieldType{}, invalid
+	}
+	size, err := strconv.Atoi(arg[1:])
+	if err != nil || size < 1 || size > 64 || (!t.signed && size == 64) {
+		return bitfieldType{}, invalid
+	}
+	t.bits = size
+	return t, nil
+}
+
+// parseBitfieldOffset accepts a plain bit offset or a #N offset that is multiplied by the type width.
+func parseBitfieldOffset(arg string, t bitfieldType) (int, error) {
+	multiply := strings.HasPrefix(arg, "#")
+	if multiply {
+		arg = arg[1:]
+	}
+	offset, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, ErrBitOffset
+	}
+	if multiply {
+		if offset > maxBitOffset/int64(t.bits) {
+			return 0, ErrBitOffset
+		}
+		offset *= int64(t.bits)
+	}
+	if offset+int64(t.bits)-1 > maxBitOffset {
+		return 0, ErrBitOffset
+	}
+	return int(offset), nil
+}
+
+func (t bitfieldType) get(buf []byte, offset int) int64 {
+	var value uint64
+	for i := 0; i < t.bits; i++ {
+		value = value<<1 | uint64(getBit(buf, offset+i))
+	}
+	if t.signed && t.bits < 64 && value&(1<<(t.bits-1)) != 0 {
+		// sign extend the negative value to the full 64 bits
+		value |= ^uint64(0) << t.bits
+	}
+	return int64(value)
+}
+
+func (t bitfieldType) set(buf []byte, offset int, value int64) {
+	for i := 0; i < t.bits; i++ {
+		bit := int(uint64(value)>>(t.bits-1-i)) & 1
+		setBit(buf, offset+i, bit)
+	}
+}
+
+// fit applies the OVERFLOW policy to value, returning false when FAIL rejects it.
+func (t bitfieldType) fit(value *big.Int, overflow string) (int64, bool) {
+	var minValue, maxValue *big.Int
+	if t.signed {
+		maxValue = new(big.Int).Lsh(big.NewInt(1), uint(t.bits-1))
+		minValue = new(big.Int).Neg(maxValue)
+		maxValue.Sub(maxValue, big.NewInt(1))
+	} else {
+		minValue = big.NewInt(0)
+		maxValue = new(big.Int).Lsh(big.NewInt(1), uint(t.bits))
+		maxValue.Sub(maxValue, big.NewInt(1))
+	}
+	if value.Cmp(minValue) >= 0 && value.Cmp(maxValue) <= 0 {
+		return value.Int64(), true
+	}
+
+	switch overflow {
+	case "SAT":
+		if value.Cmp(minValue) < 0 {
+			return minValue.Int64(), true
+		}
+		return maxValue.Int64(), true
+	case "FAIL":
+		return 0, false
+	default:
+		modulus := new(big.Int).Lsh(big.NewInt(1), uint(t.bits))
+		wrapped := new(big.Int).Mod(value, modulus)
+		if t.signed && wrapped.Cmp(maxValue) > 0 {
+			wrapped.Sub(wrapped, modulus)
+		}
+		return wrapped.Int64(), true
+	}
+}
+
+type BITFIELDCommand struct {
+	baseCommand
+}
+
+func (c *BITFIELDCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'BITFIELD' command")
+	}
+	key := args[1]
+
+	type operation struct {
+		name     string
+		t        bitfieldType
+		offset   int
+		value    int64
+		overflow string
+	}
+
+	// validate every subcommand before touching the value so errors leave it unchanged
+	var operations []operation
+	overflow := "WRAP"
+	writes := false
+	for i := 2; i < len(args); i++ {
+		name := strings.ToUpper(args[i])
+		switch name {
+		case "OVERFLOW":
+			if i+1 >= len(args) {
+				return "", ErrSyntax
+			}
+			overflow = strings.ToUpper(args[i+1])
+			if overflow != "WRAP" && overflow != "SAT" && overflow != "FAIL" {
+				return "", fmt.Errorf("Invalid OVERFLOW type specified")
+			}
+			i++
+		case "GET", "SET", "INCRBY":
+			needed := 2
+			if name != "GET" {
+				needed = 3
+			}
+			if i+needed >= len(args) {
+				return "", ErrSyntax
+			}
+			t, err := parseBitfieldType(args[i+1])
+			if err != nil {
+				return "", err
+			}
+			offset, err := parseBitfieldOffset(args[i+2], t)
+			if err != nil {
+				return "", err
+			}
+			op := operation{name: name, t: t, offset: offset, overflow: overflow}
+			if name != "GET" {
+				op.value, err = strconv.ParseInt(args[i+3], 10, 64)
+				if err != nil {
+					return "", ErrNotInteger
+				}
+				writes = true
+			}
+			operations = append(operations, op)
+			i += needed
+		default:
+			return "", ErrSyntax
+		}
+	}
+
+	current, _, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	buf := []byte(current)
+	if writes {
+		highest := 0
+		for _, op := range operations {
+			if op.name != "GET" {
+				highest = max(highest, op.offset+op.t.bits-1)
+			}
+		}
+		buf = growBytes(buf, highest>>3+1)
+	}
+
+	result := make([]any, 0, len(operations))
+	for _, op := range operations {
+		old := op.t.get(buf, op.offset)
+		switch op.name {
+		case "GET":
+			result = append(result, old)
+		case "SET":
+			value, ok := op.t.fit(big.NewInt(op.value), op.overflow)
+			if !ok {
+				result = append(result, nil)
+				continue
+			}
+			op.t.set(buf, op.offset, value)
+			result = append(result, old)
+		case "INCRBY":
+			sum := new(big.Int).Add(big.NewInt(old), big.NewInt(op.value))
+			value, ok := op.t.fit(sum, op.overflow)
+			if !ok {
+				result = append(result, nil)
+				continue
+			}
+			op.t.set(buf, op.offset, value)
+			result = append(result, value)
+		}
+	}
+
+	if writes {
+		setStringKeepTTL(c.db, key, string(buf))
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"SETBIT", "visits", "7", "1"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"SETBIT", "visits", "7", "0"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"SETBIT", "visits", "100", "1"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"STRLEN", "visits"},
+			expectedOutput: 13,
+		},
+		{
+			args:           []string{"GETBIT", "visits", "100"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"GETBIT", "visits", "10000"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"SET", "foobar", "foobar"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"BITCOUNT", "foobar"},
+			expectedOutput: 26,
+		},
+		{
+			args:           []string{"BITCOUNT", "foobar", "1", "1"},
+			expectedOutput: 6,
+		},
+		{
+			args:           []string{"BITCOUNT", "foobar", "5", "30", "BIT"},
+			expectedOutput: 17,
+		},
+		{
+			args:           []string{"SET", "mykey", "\xff\xf0\x00"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"BITPOS", "mykey", "0"},
+			expectedOutput: 12,
+		},
+		{
+			args:           []string{"SET", "mykey", "\x00\xff\xf0"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"BITPOS", "mykey", "1", "0"},
+			expectedOutput: 8,
+		},
+		{
+			args:           []string{"BITPOS", "mykey", "1", "2"},
+			expectedOutput: 16,
+		},
+		{
+			args:           []string{"BITPOS", "mykey", "1", "7", "15", "BIT"},
+			expectedOutput: 8,
+		},
+		{
+			args:           []string{"SET", "ones", "\xff\xff"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"BITPOS", "ones", "0"},
+			expectedOutput: 16,
+		},
+		{
+			args:           []string{"BITPOS", "ones", "0", "0", "-1"},
+			expectedOutput: -1,
+		},
+		{
+			args:           []string{"SET", "a", "abc"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"SET", "b", "\x0f"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"BITOP", "AND", "dest", "a", "b"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"GET", "dest"},
+			expectedOutput: "\x01\x00\x00",
+		},
+		{
+			args:           []string{"BITOP", "NOT", "dest", "b"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"GET", "dest"},
+			expectedOutput: "\xf0",
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+}
+
+func TestBitfieldCommand(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"BITFIELD", "mykey", "INCRBY", "i5", "100", "1", "GET", "u4", "0"},
+			expectedOutput: []any{int64(1), int64(0)},
+		},
+		{
+			args:           []string{"BITFIELD", "mykey", "SET", "i8", "#0", "-100", "GET", "i8", "0", "GET", "u8", "0"},
+			expectedOutput: []any{int64(0), int64(-100), int64(156)},
+		},
+		{
+			args:           []string{"BITFIELD", "counter", "INCRBY", "u2", "100", "1", "OVERFLOW", "SAT", "INCRBY", "u2", "102", "1"},
+			expectedOutput: []any{int64(1), int64(1)},
+		},
+		{
+			args:           []string{"BITFIELD", "counter", "INCRBY", "u2", "100", "5", "OVERFLOW", "SAT", "INCRBY", "u2", "102", "5"},
+			expectedOutput: []any{int64(2), int64(3)},
+		},
+		{
+			args:           []string{"BITFIELD", "counter", "OVERFLOW", "FAIL", "INCRBY", "u2", "102", "1", "GET", "u2", "102"},
+			expectedOutput: []any{nil, int64(3)},
+		},
+		{
+			args:           []string{"BITFIELD", "signed", "OVERFLOW", "WRAP", "SET", "i8", "0", "127", "INCRBY", "i8", "0", "1"},
+			expectedOutput: []any{int64(0), int64(-128)},
+		},
+		{
+			args:           []string{"BITFIELD", "wide", "SET", "i64", "0", "9223372036854775807", "INCRBY", "i64", "0", "1"},
+			expectedOutput: []any{int64(0), int64(-9223372036854775808)},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	command, _ := NewCommand("BITFIELD", db, []string{"BITFIELD", "mykey", "GET", "u64", "0"})
+	_, err := command.ExecuteCommand()
+	assert.Error(t, err)
+}
@@ -1,59 +1,62 @@
 package commands
 
 import (
-	"fmt"
-	"strings"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/db"
 )
 
-func NewCallback(name string, db *db.Db, args []string) (Command, error) {
-	b := baseCommand{
-		db:       db,
-		args:     args,
-		Response: make(chan []byte),
+// blockOn parks a blocking command until serve succeeds on one of keys, or
+// until timeout seconds have passed, where 0 blocks forever, and it replies
+// timeoutReply. serve replies nil when a key has nothing for the client.
+// blockOn runs on the event loop; the loop then calls Block off the loop and
+// runs the Callback, which replies, back on it.
+func (c *baseCommand) blockOn(keys []string, timeout float64, timeoutReply any, serve func(key string) (any, error)) {
+	var output any
+	var outputErr error
+	waiter := db.NewWaiter(keys, func(key string) bool {
+		result, err := serve(key)
+		if err == nil && result == nil {
+			return false
+		}
+		output, outputErr = result, err
+		return true
+	})
+	store := c.db
+	store.Block(waiter)
+
+	c.block = func() {
+		if timeout == 0 {
+			<-waiter.Ready
+			return
+		}
+		timer := time.NewTimer(time.Duration(timeout * float64(time.Second)))
+		defer timer.Stop()
+		select {
+		case <-waiter.Ready:
+		case <-timer.C:
+		}
 	}
-	switch strings.ToUpper(name) {
-	case "BLPOP":
-		return &BLPOPCallback{baseCommand: b}, nil
-	default:
-		return nil, fmt.Errorf("unknown command '%s'", name)
+	c.callback = &blockedCallback{
+		baseCommand: baseCommand{db: store, args: c.args, Response: c.Response},
+		reply: func() (any, error) {
+			// a push may have served the client after its timer fired, the loop
+			// decides which one came first
+			if !waiter.Served() {
+				store.Unblock(waiter)
+				return timeoutReply, nil
+			}
+			return output, outputErr
+		},
 	}
 }
 
-type BLPOPCallback struct {
+// blockedCallback replies to a blocked command once it was served or timed out.
+type blockedCallback struct {
 	baseCommand
+	reply func() (any, error)
 }
 
-func (c *BLPOPCallback) ExecuteCommand() (any, error) {
-	args := c.args
-	key := args[1]
-
-	val, ok := c.db.DbMap[key]
-
-	if !ok {
-		return []string{}, nil
-	}
-	valAsList, ok := val.Value.([]string)
-	if !ok {
-		return "", fmt.Errorf("wrong number of arguments for 'LLEN' command")
-	}
-	first := valAsList[0]
-	val.Value = valAsList[1:]
-
-	if val.HasExpiryDate && time.Now().After(val.ExpireAt) {
-		delete(c.db.DbMap, key)
-		delete(c.db.ListChannels, key)
-		return "-1", nil
-	}
-	if len(valAsList)-1 == 0 {
-		delete(c.db.DbMap, key)
-		delete(c.db.ListChannels, key)
-	} else {
-		c.db.ListChannels[key] <- true
-	}
-
-	result := []string{key, first}
-	return result, nil
+func (c *blockedCallback) ExecuteCommand() (any, error) {
+	return c.reply()
 }
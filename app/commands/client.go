@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+)
+
+// Client is the state of one connection that outlives a single command, such
+// as the database it has selected.
+type Client struct {
+	Databases *db.Databases
+	DbIndex   int
+}
+
+func NewClient(databases *db.Databases) *Client {
+	return &Client{Databases: databases}
+}
+
+// Db returns the database the client currently has selected.
+func (c *Client) Db() *db.Db {
+	return c.Databases.Dbs[c.DbIndex]
+}
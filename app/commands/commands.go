@@ -1,13 +1,24 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+)
+
+var (
+	ErrWrongType  = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	ErrNotInteger = errors.New("value is not an integer or out of range")
+	ErrNotFloat   = errors.New("value is not a valid float")
+	ErrOverflow   = errors.New("increment or decrement would overflow")
+	ErrSyntax     = errors.New("syntax error")
 )
 
 type baseCommand struct {
@@ -16,6 +27,9 @@ type baseCommand struct {
 	Response   chan []byte
 	isBlocking bool
 	callback   Command
+	// block waits off the event loop until a blocked command can reply
+	block  func()
+	client *Client
 }
 
 func (c *baseCommand) GetResponseChan() chan []byte {
@@ -30,6 +44,14 @@ func (c *baseCommand) IsBlocking() bool {
 func (c *baseCommand) Callback() Command {
 	return c.callback
 }
+func (c *baseCommand) Block() {
+	if c.block != nil {
+		c.block()
+	}
+}
+func (c *baseCommand) SetClient(client *Client) {
+	c.client = client
+}
 func (c *baseCommand) GetName() string {
 	if len(c.args) == 0 {
 		return ""
@@ -42,7 +64,9 @@ type Command interface {
 	IsBlocking() bool
 	GetResponseChan() chan []byte
 	Callback() Command
+	Block()
 	SetResponseChan(newChan chan []byte)
+	SetClient(client *Client)
 	GetName() string
 }
 
@@ -66,17 +90,203 @@ func NewCommand(name string, db *db.Db, args []string) (Command, error) {
 		return &RPUSHCommand{baseCommand: b}, nil
 	case "LPUSH":
 		return &LPUSHCommand{baseCommand: b}, nil
+	case "RPUSHX":
+		return &RPUSHCommand{baseCommand: b, onlyExisting: true}, nil
+	case "LPUSHX":
+		return &LPUSHCommand{baseCommand: b, onlyExisting: true}, nil
 	case "LLEN":
 		return &LLENCommand{baseCommand: b}, nil
 	case "LPOP":
 		return &LPOPCommand{baseCommand: b}, nil
+	case "RPOP":
+		return &RPOPCommand{baseCommand: b}, nil
+	case "LINDEX":
+		return &LINDEXCommand{baseCommand: b}, nil
+	case "LSET":
+		return &LSETCommand{baseCommand: b}, nil
+	case "LINSERT":
+		return &LINSERTCommand{baseCommand: b}, nil
+	case "LREM":
+		return &LREMCommand{baseCommand: b}, nil
+	case "LTRIM":
+		return &LTRIMCommand{baseCommand: b}, nil
+	case "LPOS":
+		return &LPOSCommand{baseCommand: b}, nil
 	case "BLPOP":
 		b.isBlocking = true
 		return &BLPOPCommand{baseCommand: b}, nil
+	case "BRPOP":
+		b.isBlocking = true
+		return &BLPOPCommand{baseCommand: b, fromTail: true}, nil
+	case "LMPOP":
+		return &LMPOPCommand{baseCommand: b}, nil
+	case "BLMPOP":
+		b.isBlocking = true
+		return &LMPOPCommand{baseCommand: b, blocking: true}, nil
+	case "LMOVE":
+		return &LMOVECommand{baseCommand: b}, nil
+	case "RPOPLPUSH":
+		return &LMOVECommand{baseCommand: b, legacy: true}, nil
+	case "BLMOVE":
+		b.isBlocking = true
+		return &BLMOVECommand{baseCommand: b}, nil
+	case "BRPOPLPUSH":
+		b.isBlocking = true
+		return &BLMOVECommand{baseCommand: b, legacy: true}, nil
 	case "LRANGE":
 		return &LRANGECommand{baseCommand: b}, nil
 	case "TYPE":
 		return &TypeCommand{baseCommand: b}, nil
+	case "INCR":
+		return &INCRCommand{baseCommand: b}, nil
+	case "DECR":
+		return &DECRCommand{baseCommand: b}, nil
+	case "INCRBY":
+		return &INCRBYCommand{baseCommand: b}, nil
+	case "DECRBY":
+		return &DECRBYCommand{baseCommand: b}, nil
+	case "INCRBYFLOAT":
+		return &INCRBYFLOATCommand{baseCommand: b}, nil
+	case "APPEND":
+		return &APPENDCommand{baseCommand: b}, nil
+	case "STRLEN":
+		return &STRLENCommand{baseCommand: b}, nil
+	case "GETRANGE":
+		return &GETRANGECommand{baseCommand: b}, nil
+	case "SETRANGE":
+		return &SETRANGECommand{baseCommand: b}, nil
+	case "MGET":
+		return &MGETCommand{baseCommand: b}, nil
+	case "MSET":
+		return &MSETCommand{baseCommand: b}, nil
+	case "MSETNX":
+		return &MSETNXCommand{baseCommand: b}, nil
+	case "GETDEL":
+		return &GETDELCommand{baseCommand: b}, nil
+	case "GETSET":
+		return &GETSETCommand{baseCommand: b}, nil
+	case "GETEX":
+		return &GETEXCommand{baseCommand: b}, nil
+	case "SETBIT":
+		return &SETBITCommand{baseCommand: b}, nil
+	case "GETBIT":
+		return &GETBITCommand{baseCommand: b}, nil
+	case "BITCOUNT":
+		return &BITCOUNTCommand{baseCommand: b}, nil
+	case "BITPOS":
+		return &BITPOSCommand{baseCommand: b}, nil
+	case "BITOP":
+		return &BITOPCommand{baseCommand: b}, nil
+	case "BITFIELD":
+		return &BITFIELDCommand{baseCommand: b}, nil
+	case "LCS":
+		return &LCSCommand{baseCommand: b}, nil
+	case "DEL":
+		return &DELCommand{baseCommand: b}, nil
+	case "UNLINK":
+		return &UNLINKCommand{baseCommand: b}, nil
+	case "EXISTS":
+		return &EXISTSCommand{baseCommand: b}, nil
+	case "EXPIRE":
+		return &ExpireCommand{baseCommand: b, unit: "EX"}, nil
+	case "PEXPIRE":
+		return &ExpireCommand{baseCommand: b, unit: "PX"}, nil
+	case "EXPIREAT":
+		return &ExpireCommand{baseCommand: b, unit: "EXAT"}, nil
+	case "PEXPIREAT":
+		return &ExpireCommand{baseCommand: b, unit: "PXAT"}, nil
+	case "TTL":
+		return &TTLCommand{baseCommand: b}, nil
+	case "PTTL":
+		return &TTLCommand{baseCommand: b, milliseconds: true}, nil
+	case "EXPIRETIME":
+		return &TTLCommand{baseCommand: b, absolute: true}, nil
+	case "PEXPIRETIME":
+		return &TTLCommand{baseCommand: b, milliseconds: true, absolute: true}, nil
+	case "PERSIST":
+		return &PERSISTCommand{baseCommand: b}, nil
+	case "KEYS":
+		return &KEYSCommand{baseCommand: b}, nil
+	case "SCAN":
+		return &SCANCommand{baseCommand: b}, nil
+	case "RENAME":
+		return &RENAMECommand{baseCommand: b}, nil
+	case "RENAMENX":
+		return &RENAMENXCommand{baseCommand: b}, nil
+	case "COPY":
+		return &COPYCommand{baseCommand: b}, nil
+	case "OBJECT":
+		return &OBJECTCommand{baseCommand: b}, nil
+	case "TOUCH":
+		return &TOUCHCommand{baseCommand: b}, nil
+	case "DUMP":
+		return &DUMPCommand{baseCommand: b}, nil
+	case "RESTORE":
+		return &RESTORECommand{baseCommand: b}, nil
+	case "SELECT":
+		return &SELECTCommand{baseCommand: b}, nil
+	case "MOVE":
+		return &MOVECommand{baseCommand: b}, nil
+	case "SWAPDB":
+		return &SWAPDBCommand{baseCommand: b}, nil
+	case "FLUSHDB":
+		return &FLUSHDBCommand{baseCommand: b}, nil
+	case "FLUSHALL":
+		return &FLUSHALLCommand{baseCommand: b}, nil
+	case "DBSIZE":
+		return &DBSIZECommand{baseCommand: b}, nil
+	case "HSET":
+		return &HSETCommand{baseCommand: b}, nil
+	case "HSETNX":
+		return &HSETNXCommand{baseCommand: b}, nil
+	case "HGET":
+		return &HGETCommand{baseCommand: b}, nil
+	case "HMGET":
+		return &HMGETCommand{baseCommand: b}, nil
+	case "HDEL":
+		return &HDELCommand{baseCommand: b}, nil
+	case "HLEN":
+		return &HLENCommand{baseCommand: b}, nil
+	case "HEXISTS":
+		return &HEXISTSCommand{baseCommand: b}, nil
+	case "HSTRLEN":
+		return &HSTRLENCommand{baseCommand: b}, nil
+	case "HGETALL":
+		return &HGETALLCommand{baseCommand: b, fields: true, values: true}, nil
+	case "HKEYS":
+		return &HGETALLCommand{baseCommand: b, fields: true}, nil
+	case "HVALS":
+		return &HGETALLCommand{baseCommand: b, values: true}, nil
+	case "HINCRBY":
+		return &HINCRBYCommand{baseCommand: b}, nil
+	case "HINCRBYFLOAT":
+		return &HINCRBYFLOATCommand{baseCommand: b}, nil
+	case "HEXPIRE":
+		return &HEXPIRECommand{baseCommand: b, unit: "EX"}, nil
+	case "HPEXPIRE":
+		return &HEXPIRECommand{baseCommand: b, unit: "PX"}, nil
+	case "HEXPIREAT":
+		return &HEXPIRECommand{baseCommand: b, unit: "EXAT"}, nil
+	case "HPEXPIREAT":
+		return &HEXPIRECommand{baseCommand: b, unit: "PXAT"}, nil
+	case "HTTL":
+		return &HTTLCommand{baseCommand: b}, nil
+	case "HPTTL":
+		return &HTTLCommand{baseCommand: b, milliseconds: true}, nil
+	case "HEXPIRETIME":
+		return &HTTLCommand{baseCommand: b, absolute: true}, nil
+	case "HPEXPIRETIME":
+		return &HTTLCommand{baseCommand: b, milliseconds: true, absolute: true}, nil
+	case "HPERSIST":
+		return &HPERSISTCommand{baseCommand: b}, nil
+	case "HGETEX":
+		return &HGETEXCommand{baseCommand: b}, nil
+	case "HSETEX":
+		return &HSETEXCommand{baseCommand: b}, nil
+	case "SORT":
+		return &SORTCommand{baseCommand: b}, nil
+	case "SORT_RO":
+		return &SORTCommand{baseCommand: b, readOnly: true}, nil
 	default:
 		return nil, fmt.Errorf("unknown command '%s'", name)
 	}
@@ -130,106 +340,192 @@ type SetCommand struct {
 
 func (c *SetCommand) ExecuteCommand() (any, error) {
 	args := c.args
-	if len(args) < 3 || len(args) == 4 {
+	if len(args) < 3 {
 		return "", fmt.Errorf("wrong number of arguments for 'SET' command")
 	}
 	key := args[1]
 	value := args[2]
 
-	dbVal := db.MapValue{Value: value}
+	var nx, xx, get, keepTTL bool
+	var expiryOption string
+	var expireAt time.Time
+	for i := 3; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		switch option {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "GET":
+			get = true
+		case "KEEPTTL":
+			keepTTL = true
+		case "EX", "PX", "EXAT", "PXAT":
+			if expiryOption != "" {
+				return "", ErrSyntax
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("wrong number of arguments for 'SET' command")
+			}
+			var err error
+			expireAt, err = parseExpiry(option, args[i+1], "set")
+			if err != nil {
+				return "", err
+			}
+			expiryOption = option
+			i++
+		default:
+			return "", ErrSyntax
+		}
+	}
+	if (nx && xx) || (keepTTL && expiryOption != "") {
+		return "", ErrSyntax
+	}
 
-	if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
-		milliseconds, err := strconv.ParseInt(args[4], 10, 64)
-		if err != nil {
-			return "", fmt.Errorf("value is not an integer or out of range")
+	existing, exists := c.db.GetEntry(key)
+	var oldValue any
+	if get && exists {
+		str, ok := existing.Value.(string)
+		if !ok {
+			return "", ErrWrongType
 		}
-		dbVal.HasExpiryDate = true
-		dbVal.ExpireAt = time.Now().Add(time.Duration(milliseconds) * time.Millisecond)
+		oldValue = str
+	}
+	// a failed NX/XX condition replies nil, or the old value when GET was given
+	if (nx && exists) || (xx && !exists) {
+		return oldValue, nil
 	}
 
-	c.db.DbMap[key] = &dbVal
+	dbVal := db.MapValue{Value: value}
+	if expiryOption != "" {
+		dbVal.HasExpiryDate = true
+		dbVal.ExpireAt = expireAt
+	} else if keepTTL && exists {
+		dbVal.HasExpiryDate = existing.HasExpiryDate
+		dbVal.ExpireAt = existing.ExpireAt
+	}
+	c.db.SetEntry(key, &dbVal)
 
+	if get {
+		return oldValue, nil
+	}
 	return "OK", nil
 }
 
+// parseExpiry turns an EX, PX, EXAT or PXAT argument into an absolute expiry time.
+func parseExpiry(option string, arg string, commandName string) (time.Time, error) {
+	number, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrNotInteger
+	}
+	invalidExpire := fmt.Errorf("invalid expire time in '%s' command", commandName)
+	if number <= 0 {
+		return time.Time{}, invalidExpire
+	}
+	milliseconds, ok := expiryToUnixMilli(option, number)
+	if !ok {
+		return time.Time{}, invalidExpire
+	}
+	return time.UnixMilli(milliseconds), nil
+}
+
+// expiryToUnixMilli converts an amount given as EX/PX (relative) or EXAT/PXAT
+// (absolute) into unix milliseconds, reporting false if the result overflows.
+func expiryToUnixMilli(option string, number int64) (int64, bool) {
+	milliseconds := number
+	if option == "EX" || option == "EXAT" {
+		if number > math.MaxInt64/1000 || number < math.MinInt64/1000 {
+			return 0, false
+		}
+		milliseconds = number * 1000
+	}
+	if option == "EX" || option == "PX" {
+		now := time.Now().UnixMilli()
+		if milliseconds > math.MaxInt64-now {
+			return 0, false
+		}
+		milliseconds += now
+	}
+	return milliseconds, true
+}
+
+// RPUSHCommand implements RPUSH and RPUSHX, which only pushes onto a list
+// that already exists.
 type RPUSHCommand struct {
 	baseCommand
+	onlyExisting bool
 }
 
 func (c *RPUSHCommand) ExecuteCommand() (any, error) {
 	args := c.args
 	if len(args) < 3 {
-		return "", fmt.Errorf("wrong number of arguments for 'RPUSH' command")
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(c.GetName()))
 	}
 	key := args[1]
 
-	// TO DO - refactor this a bit to use the GetValue method
-	_, ok := c.db.DbMap[key]
+	// GetEntry drops an expired list first, so pushing to it starts a new one
+	val, ok := c.db.GetEntry(key)
 
 	if !ok {
-		c.db.DbMap[key] = &db.MapValue{
-			Value: make([]string, 0),
-			SetAt: time.Now(),
+		if c.onlyExisting {
+			return 0, nil
 		}
-		if _, ok := c.db.ListChannels[key]; !ok {
-			c.db.ListChannels[key] = make(chan bool, 1)
+		val = &db.MapValue{
+			Value: quicklist.New(),
+			SetAt: time.Now(),
 		}
+		c.db.SetEntry(key, val)
+	}
+	list, ok := val.Value.(*quicklist.List)
+	if !ok {
+		return "", ErrWrongType
 	}
-	val := c.db.DbMap[key]
 	for i := 2; i < len(args); i++ {
-		val.Value = append(val.Value.([]string), args[i])
+		list.PushBack(args[i])
 	}
 
-	listSize := len(val.Value.([]string))
-	if val.HasExpiryDate && time.Now().After(val.ExpireAt) {
-		delete(c.db.DbMap, key)
-		return "-1", nil
-	}
-	select {
-	case c.db.ListChannels[key] <- true:
-	default:
-	}
+	listSize := list.Len()
+	signalListReady(c.db, key)
 
 	return listSize, nil
 }
 
+// LPUSHCommand implements LPUSH and LPUSHX, see RPUSHCommand.
 type LPUSHCommand struct {
 	baseCommand
+	onlyExisting bool
 }
 
 func (c *LPUSHCommand) ExecuteCommand() (any, error) {
 	args := c.args
 	if len(args) < 3 {
-		return "", fmt.Errorf("wrong number of arguments for 'LPUSH' command")
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(c.GetName()))
 	}
 	key := args[1]
 
-	// TO DO - refactor this a bit to use the GetValue method
-	_, ok := c.db.DbMap[key]
+	// GetEntry drops an expired list first, so pushing to it starts a new one
+	val, ok := c.db.GetEntry(key)
 
 	if !ok {
-		c.db.DbMap[key] = &db.MapValue{
-			Value: make([]string, 0),
-			SetAt: time.Now(),
+		if c.onlyExisting {
+			return 0, nil
 		}
-		if _, ok := c.db.ListChannels[key]; !ok {
-			c.db.ListChannels[key] = make(chan bool, 1)
+		val = &db.MapValue{
+			Value: quicklist.New(),
+			SetAt: time.Now(),
 		}
+		c.db.SetEntry(key, val)
+	}
+	list, ok := val.Value.(*quicklist.List)
+	if !ok {
+		return "", ErrWrongType
 	}
-	val := c.db.DbMap[key]
 	for i := 2; i < len(args); i++ {
-		val.Value = append([]string{args[i]}, val.Value.([]string)...)
+		list.PushFront(args[i])
 	}
 
-	listSize := len(val.Value.([]string))
-	if val.HasExpiryDate && time.Now().After(val.ExpireAt) {
-		delete(c.db.DbMap, key)
-		return "-1", nil
-	}
-	select {
-	case c.db.ListChannels[key] <- true:
-	default:
-	}
+	listSize := list.Len()
+	signalListReady(c.db, key)
 	return listSize, nil
 }
 
@@ -244,24 +540,17 @@ func (c *LLENCommand) ExecuteCommand() (any, error) {
 	}
 	key := args[1]
 
-	// TO DO - refactor this a bit to use the GetValue method
-	val, ok := c.db.DbMap[key]
+	val, ok := c.db.GetEntry(key)
 
 	if !ok {
 		return 0, nil
 	}
-	valAsList, ok := val.Value.([]string)
+	valAsList, ok := val.Value.(*quicklist.List)
 	if !ok {
 		return "", fmt.Errorf("value not a list")
 	}
 
-	listSize := len(valAsList)
-	if val.HasExpiryDate && time.Now().After(val.ExpireAt) {
-		delete(c.db.DbMap, key)
-		return "-1", nil
-	}
-
-	return listSize, nil
+	return valAsList.Len(), nil
 }
 
 type LPOPCommand struct {
@@ -269,94 +558,46 @@ type LPOPCommand struct {
 }
 
 func (c *LPOPCommand) ExecuteCommand() (any, error) {
-	fmt.Println("executing  LPOP")
-	args := c.args
-	if len(args) > 3 {
-		return "", fmt.Errorf("wrong number of arguments for 'LLEN' command")
-	}
-	key := args[1]
-	var numberOfElements = 1
-	var err error
-	if len(args) == 3 {
-		numberOfElements, err = strconv.Atoi(args[2])
-		if err != nil {
-			return "", fmt.Errorf("argument to pop must be an integer")
-		}
-	}
-
-	// TO DO - refactor this a bit to use the GetValue method
-	val, ok := c.db.DbMap[key]
-
-	if !ok {
-		return 0, nil
-	}
-	valAsList, ok := val.Value.([]string)
-	if !ok {
-		return "", fmt.Errorf("value not a list")
-	}
-	var first any
-	if numberOfElements == 1 {
-		first = valAsList[0]
-	} else {
-		first = valAsList[:numberOfElements]
-	}
-	val.Value = valAsList[numberOfElements:]
-
-	if val.HasExpiryDate && time.Now().After(val.ExpireAt) {
-		delete(c.db.DbMap, key)
-		delete(c.db.ListChannels, key)
-		return "-1", nil
-	}
-	if len(valAsList)-numberOfElements == 0 {
-		delete(c.db.DbMap, key)
-		delete(c.db.ListChannels, key)
+	count, err := parsePopCount(c.args, "LPOP")
+	if err != nil {
+		return "", err
 	}
-
-	return first, nil
+	return popList(c.db, c.args[1], count, len(c.args) == 3, false)
 }
 
+// BLPOPCommand implements BLPOP and BRPOP. The first of the keys holding a
+// list is popped right away, otherwise the client waits for a push to any of
+// them behind the clients that blocked earlier.
 type BLPOPCommand struct {
 	baseCommand
+	fromTail bool
 }
 
 func (c *BLPOPCommand) ExecuteCommand() (any, error) {
-	fmt.Println("executing  BLPOP")
 	args := c.args
-	if len(args) != 3 {
-		return "", fmt.Errorf("wrong number of arguments for 'BLPOP' command")
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(c.GetName()))
 	}
-	key := args[1]
-	var timeout = 0.0
-	var err error
-	timeout, err = strconv.ParseFloat(args[2], 32)
+	keys := args[1 : len(args)-1]
+	timeout, err := parseBlockTimeout(args[len(args)-1])
 	if err != nil {
-		return "", fmt.Errorf("argument to blpop must be an integer")
-	}
-
-	// TO DO - refactor this a bit to use the GetValue method
-	if _, ok := c.db.ListChannels[key]; !ok {
-		c.db.ListChannels[key] = make(chan bool, 1)
-	}
-	if timeout == 0 {
-		<-c.db.ListChannels[key]
-	} else {
-		blocking := true
-		startTime := time.Now()
-		for blocking {
-			select {
-			case <-c.db.ListChannels[key]:
-				blocking = false
-			default:
-				if time.Since(startTime) > time.Duration(timeout*float64(time.Second)) {
-					blocking = false
-					return []string{"-1"}, nil
-				}
-			}
-		}
+		return "", err
 	}
-	c.callback, _ = NewCallback("BLPOP", c.db, []string{"LPOP", key})
-	c.callback.SetResponseChan(c.Response)
 
+	pop := func(key string) (any, error) {
+		element, err := popList(c.db, key, 1, false, c.fromTail)
+		if err != nil || element == nil {
+			return element, err
+		}
+		return []string{key, element.(string)}, nil
+	}
+	for _, key := range keys {
+		output, err := pop(key)
+		if err != nil || output != nil {
+			return output, err
+		}
+	}
+	c.blockOn(keys, timeout, nullArray, pop)
 	return nil, nil
 }
 
@@ -385,15 +626,15 @@ func (c *LRANGECommand) ExecuteCommand() (any, error) {
 		return "", fmt.Errorf("wrong value for argument,expected integer")
 	}
 
-	valAsList, ok := val.([]string)
+	valAsList, ok := val.(*quicklist.List)
 	if !ok {
 		return "", fmt.Errorf("value not a list")
 	}
 	if startIndex < 0 {
-		startIndex = len(valAsList) + startIndex
+		startIndex = valAsList.Len() + startIndex
 	}
 	if stopIndex < 0 {
-		stopIndex = len(valAsList) + stopIndex
+		stopIndex = valAsList.Len() + stopIndex
 	}
 
 	if startIndex < 0 {
@@ -402,16 +643,16 @@ func (c *LRANGECommand) ExecuteCommand() (any, error) {
 	if stopIndex < 0 {
 		stopIndex = 0
 	}
-	if startIndex >= len(valAsList) {
+	if startIndex >= valAsList.Len() {
 		return []string{}, nil
 	}
-	if stopIndex >= len(valAsList) {
-		stopIndex = len(valAsList) - 1
+	if stopIndex >= valAsList.Len() {
+		stopIndex = valAsList.Len() - 1
 	}
 	if startIndex > stopIndex {
 		return []string{}, nil
 	}
-	return valAsList[startIndex : stopIndex+1], nil
+	return valAsList.Range(startIndex, stopIndex), nil
 }
 
 type TypeCommand struct {
@@ -425,19 +666,26 @@ func (c *TypeCommand) ExecuteCommand() (any, error) {
 	}
 	key := args[1]
 
-	val, ok := c.db.GetValue(key)
+	val, ok := c.db.PeekEntry(key)
 	if !ok {
 		return "none", nil
 	}
 
+	return typeName(val.Value)
+}
+
+// typeName reports the Redis type name of a stored value.
+func typeName(val any) (string, error) {
+	if _, ok := val.(*quicklist.List); ok {
+		return "list", nil
+	}
 	valType := reflect.TypeOf(val)
 	switch valType.Kind() {
 	case reflect.String:
 		return "string", nil
-	case reflect.Slice:
-		return "list", nil
+	case reflect.Map:
+		return "hash", nil
 	default:
 		return "", fmt.Errorf("unsupported type %s", valType.Kind().String())
 	}
-
 }
@@ -2,10 +2,12 @@ package commands
 
 import (
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -98,7 +100,7 @@ func TestRPUSHCommand(t *testing.T) {
 
 func TestLPOPCommand(t *testing.T) {
 	db := db.NewDb()
-	db.SetValue("foo", []string{"strawberry", "apple", "orange"})
+	db.SetValue("foo", quicklist.New("strawberry", "apple", "orange"))
 	testCases := []struct {
 		args           []string
 		expectedOutput any
@@ -123,3 +125,79 @@ func TestLPOPCommand(t *testing.T) {
 	_, ok := db.GetValue("foo")
 	assert.Equal(t, false, ok)
 }
+
+func TestSetOptions(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			args:           []string{"SET", "lock", "token1", "NX", "PX", "30000"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"SET", "lock", "token2", "NX", "PX", "30000"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"SET", "lock", "token3", "XX", "KEEPTTL", "GET"},
+			expectedOutput: "token1",
+		},
+		{
+			args:           []string{"SET", "missing", "value", "XX"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"SET", "lock", "token4", "NX", "XX"},
+			expectedOutput: "",
+			expectedError:  ErrSyntax,
+		},
+		{
+			args:           []string{"SET", "lock", "token4", "EX", "10", "KEEPTTL"},
+			expectedOutput: "",
+			expectedError:  ErrSyntax,
+		},
+		{
+			args:           []string{"SET", "lock", "token4", "EX", "10", "PX", "100"},
+			expectedOutput: "",
+			expectedError:  ErrSyntax,
+		},
+		{
+			args:           []string{"SET", "lock", "token4", "EX", "0"},
+			expectedOutput: "",
+			expectedError:  errors.New("invalid expire time in 'set' command"),
+		},
+		{
+			args:           []string{"SET", "lock", "token4", "FOO"},
+			expectedOutput: "",
+			expectedError:  ErrSyntax,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, _ := NewCommand("SET", db, tt.args)
+		output, err := command.ExecuteCommand()
+		assert.Equal(t, tt.expectedError, err)
+		assert.Equal(t, tt.expectedOutput, output)
+	}
+
+	entry, ok := db.GetEntry("lock")
+	assert.True(t, ok)
+	assert.Equal(t, "token3", entry.Value)
+	assert.True(t, entry.HasExpiryDate)
+	_, ok = db.GetValue("missing")
+	assert.False(t, ok)
+}
+
+func TestSetExpireAt(t *testing.T) {
+	db := db.NewDb()
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).UnixMilli(), 10)
+	command, _ := NewCommand("SET", db, []string{"SET", "foo", "bar", "PXAT", past})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", output)
+	_, ok := db.GetValue("foo")
+	assert.False(t, ok)
+}
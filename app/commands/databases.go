@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+)
+
+var ErrDbIndexRange = errors.New("DB index is out of range")
+
+// siblingDb parses a database index argument and returns that database.
+func siblingDb(store *db.Db, arg string) (*db.Db, error) {
+	index, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, ErrNotInteger
+	}
+	target, ok := store.Sibling(index)
+	if !ok {
+		return nil, ErrDbIndexRange
+	}
+	return target, nil
+}
+
+type SELECTCommand struct {
+	baseCommand
+}
+
+func (c *SELECTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'SELECT' command")
+	}
+	index, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid DB index")
+	}
+	if c.client == nil {
+		// without a connection there is nothing to remember the choice on
+		if _, ok := c.db.Sibling(index); !ok {
+			return "", ErrDbIndexRange
+		}
+		return "OK", nil
+	}
+	if _, ok := c.client.Databases.Get(index); !ok {
+		return "", ErrDbIndexRange
+	}
+	c.client.DbIndex = index
+	return "OK", nil
+}
+
+type MOVECommand struct {
+	baseCommand
+}
+
+func (c *MOVECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'MOVE' command")
+	}
+	key := args[1]
+	target, err := siblingDb(c.db, args[2])
+	if err != nil {
+		return "", err
+	}
+	if target == c.db {
+		return "", fmt.Errorf("source and destination objects are the same")
+	}
+
+	entry, ok := c.db.GetEntry(key)
+	if !ok {
+		return 0, nil
+	}
+	if _, exists := target.GetEntry(key); exists {
+		return 0, nil
+	}
+	c.db.DelValue(key)
+	target.PutEntry(key, entry)
+	signalListReady(target, key)
+	return 1, nil
+}
+
+type SWAPDBCommand struct {
+	baseCommand
+}
+
+func (c *SWAPDBCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'SWAPDB' command")
+	}
+	first, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid first DB index")
+	}
+	second, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid second DB index")
+	}
+	firstDb, ok := c.db.Sibling(first)
+	if !ok {
+		return "", ErrDbIndexRange
+	}
+	secondDb, ok := c.db.Sibling(second)
+	if !ok {
+		return "", ErrDbIndexRange
+	}
+	if firstDb == secondDb {
+		return "OK", nil
+	}
+
+	// only the data is exchanged, clients blocked on a list keep waiting in the
+	// database they selected and may now find their key there
+	firstDb.SwapData(secondDb)
+	for _, store := range []*db.Db{firstDb, secondDb} {
+		for _, key := range store.WaitingKeys() {
+			signalListReady(store, key)
+		}
+	}
+	return "OK", nil
+}
+
+// parseFlushMode reads the optional SYNC or ASYNC argument of FLUSHDB and FLUSHALL.
+func parseFlushMode(args []string) (bool, error) {
+	if len(args) > 2 {
+		return false, ErrSyntax
+	}
+	if len(args) == 1 {
+		return false, nil
+	}
+	switch strings.ToUpper(args[1]) {
+	case "SYNC":
+		return false, nil
+	case "ASYNC":
+		return true, nil
+	default:
+		return false, ErrSyntax
+	}
+}
+
+type FLUSHDBCommand struct {
+	baseCommand
+}
+
+func (c *FLUSHDBCommand) ExecuteCommand() (any, error) {
+	async, err := parseFlushMode(c.args)
+	if err != nil {
+		return "", err
+	}
+	c.db.Flush(async)
+	return "OK", nil
+}
+
+type FLUSHALLCommand struct {
+	baseCommand
+}
+
+func (c *FLUSHALLCommand) ExecuteCommand() (any, error) {
+	async, err := parseFlushMode(c.args)
+	if err != nil {
+		return "", err
+	}
+	for _, store := range c.db.Siblings() {
+		store.Flush(async)
+	}
+	return "OK", nil
+}
+
+type DBSIZECommand struct {
+	baseCommand
+}
+
+func (c *DBSIZECommand) ExecuteCommand() (any, error) {
+	if len(c.args) != 1 {
+		return "", fmt.Errorf("wrong number of arguments for 'DBSIZE' command")
+	}
+	return len(c.db.DbMap), nil
+}
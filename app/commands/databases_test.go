@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+// runClientCommand builds a command the way a connection does, against the
+// client's selected database.
+func runClientCommand(client *Client, args ...string) (any, error) {
+	command, err := NewCommand(args[0], client.Db(), args)
+	if err != nil {
+		return nil, err
+	}
+	command.SetClient(client)
+	return command.ExecuteCommand()
+}
+
+func TestSelectMoveAndSwapdb(t *testing.T) {
+	databases := db.NewDatabases(4)
+	client := NewClient(databases)
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			args:           []string{"SET", "service", "billing"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"SELECT", "2"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"GET", "service"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"SET", "service", "search"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"MOVE", "service", "0"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"MOVE", "service", "1"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXISTS", "service"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"SWAPDB", "1", "2"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"GET", "service"},
+			expectedOutput: "search",
+		},
+		{
+			args:           []string{"SELECT", "16"},
+			expectedOutput: "",
+			expectedError:  ErrDbIndexRange,
+		},
+		{
+			args:           []string{"COPY", "service", "service", "DB", "0", "REPLACE"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"SELECT", "0"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"GET", "service"},
+			expectedOutput: "search",
+		},
+	}
+
+	for _, tt := range testCases {
+		output, err := runClientCommand(client, tt.args...)
+		assert.Equal(t, tt.expectedError, err, tt.args)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+}
+
+func TestSwapdbWakesBlockedClients(t *testing.T) {
+	databases := db.NewDatabases(2)
+	databases.Dbs[1].SetValue("jobs", quicklist.New("a", "b"))
+	blocked := blockClient(t, databases.Dbs[0], "BLPOP", "jobs", "0")
+
+	client := NewClient(databases)
+	_, err := runClientCommand(client, "SWAPDB", "0", "1")
+	assert.NoError(t, err)
+
+	output, _ := blocked.Callback().ExecuteCommand()
+	assert.Equal(t, []string{"jobs", "a"}, output)
+	value, _ := databases.Dbs[0].GetValue("jobs")
+	assert.Equal(t, []string{"b"}, value.(*quicklist.List).Slice())
+}
+
+func TestFlushCommands(t *testing.T) {
+	databases := db.NewDatabases(3)
+	databases.Dbs[0].SetValue("a", "1")
+	databases.Dbs[0].SetValue("b", "2")
+	databases.Dbs[1].SetValue("c", "3")
+	databases.Dbs[2].SetValue("jobs", quicklist.New("x"))
+	blockClient(t, databases.Dbs[2], "BLPOP", "later", "0")
+	client := NewClient(databases)
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			args:           []string{"DBSIZE"},
+			expectedOutput: 2,
+		},
+		{
+			args:           []string{"FLUSHDB", "NOW"},
+			expectedOutput: "",
+			expectedError:  ErrSyntax,
+		},
+		{
+			args:           []string{"FLUSHDB", "ASYNC"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"DBSIZE"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"SELECT", "1"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"DBSIZE"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"FLUSHALL", "SYNC"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"DBSIZE"},
+			expectedOutput: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		output, err := runClientCommand(client, tt.args...)
+		assert.Equal(t, tt.expectedError, err, tt.args)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	// flushing does not unblock clients, they keep waiting for a push
+	assert.Equal(t, 1, databases.Dbs[2].Waiting("later"))
+	assert.Equal(t, 0, len(databases.Dbs[2].DbMap))
+}
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/rdb"
+)
+
+type DUMPCommand struct {
+	baseCommand
+}
+
+func (c *DUMPCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'DUMP' command")
+	}
+
+	entry, ok := c.db.GetEntry(args[1])
+	if !ok {
+		return nil, nil
+	}
+	value := entry.Value
+	if len(entry.FieldExpireAt) > 0 {
+		expiring := rdb.ExpiringHash{
+			Fields:   value.(map[string]string),
+			ExpireAt: make(map[string]int64, len(entry.FieldExpireAt)),
+		}
+		for field, expireAt := range entry.FieldExpireAt {
+			expiring.ExpireAt[field] = expireAt.UnixMilli()
+		}
+		value = expiring
+	}
+	payload, err := rdb.Dump(value)
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
+
+type RESTORECommand struct {
+	baseCommand
+}
+
+func (c *RESTORECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'RESTORE' command")
+	}
+	key := args[1]
+	ttl, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	if ttl < 0 {
+		return "", fmt.Errorf("Invalid TTL value, must be >= 0")
+	}
+
+	var replace, absoluteTTL bool
+	idleTime, freq := int64(-1), -1
+	for i := 4; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "REPLACE":
+			replace = true
+		case "ABSTTL":
+			absoluteTTL = true
+		case "IDLETIME":
+			if i+1 >= len(args) || freq != -1 {
+				return "", ErrSyntax
+			}
+			idleTime, err = strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return "", ErrNotInteger
+			}
+			if idleTime < 0 {
+				return "", fmt.Errorf("Invalid IDLETIME value, must be >= 0")
+			}
+			i++
+		case "FREQ":
+			if i+1 >= len(args) || idleTime != -1 {
+				return "", ErrSyntax
+			}
+			freq, err = strconv.Atoi(args[i+1])
+			if err != nil {
+				return "", ErrNotInteger
+			}
+			if freq < 0 || freq > 255 {
+				return "", fmt.Errorf("Invalid FREQ value, must be >= 0 and <= 255")
+			}
+			i++
+		default:
+			return "", ErrSyntax
+		}
+	}
+
+	if _, exists := c.db.GetEntry(key); exists && !replace {
+		return "", fmt.Errorf("BUSYKEY Target key name already exists.")
+	}
+	value, err := rdb.Restore([]byte(args[3]))
+	if err != nil {
+		return "", err
+	}
+
+	entry := &db.MapValue{Value: value}
+	if expiring, ok := value.(rdb.ExpiringHash); ok {
+		entry.Value = expiring.Fields
+		for field, expireAt := range expiring.ExpireAt {
+			entry.SetFieldExpiry(field, time.UnixMilli(expireAt))
+		}
+	}
+	if ttl > 0 {
+		expireAt := ttl
+		if !absoluteTTL {
+			var ok bool
+			if expireAt, ok = expiryToUnixMilli("PX", ttl); !ok {
+				return "", fmt.Errorf("Invalid TTL value, must be >= 0")
+			}
+		}
+		// a TTL that already passed only removes the key being replaced
+		if expireAt <= time.Now().UnixMilli() {
+			c.db.DelValue(key)
+			return "OK", nil
+		}
+		entry.HasExpiryDate = true
+		entry.ExpireAt = time.UnixMilli(expireAt)
+	}
+
+	c.db.SetEntry(key, entry)
+	if idleTime >= 0 {
+		entry.LastAccess = time.Now().Add(-time.Duration(idleTime) * time.Second)
+	}
+	if freq >= 0 {
+		entry.Freq = uint8(freq)
+	}
+	signalListReady(c.db, key)
+	return "OK", nil
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpAndRestore(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("greeting", "hello")
+	db.SetValue("queue", quicklist.New("a", "b", "c"))
+
+	var payloads = map[string]string{}
+	for _, key := range []string{"greeting", "queue"} {
+		command, _ := NewCommand("DUMP", db, []string{"DUMP", key})
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		payloads[key] = output.(string)
+	}
+
+	future := strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10)
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			args:           []string{"DUMP", "missing"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"RESTORE", "greeting", "0", payloads["greeting"]},
+			expectedOutput: "",
+			expectedError:  errors.New("BUSYKEY Target key name already exists."),
+		},
+		{
+			args:           []string{"RESTORE", "copy", "0", payloads["greeting"], "IDLETIME", "100"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"RESTORE", "queue", "5000", payloads["queue"], "REPLACE", "FREQ", "50"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"RESTORE", "abs", future, payloads["queue"], "ABSTTL"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"RESTORE", "bad", "0", "garbage"},
+			expectedOutput: "",
+			expectedError:  errors.New("DUMP payload version or checksum are wrong"),
+		},
+		{
+			args:           []string{"RESTORE", "bad", "0", payloads["queue"], "IDLETIME", "1", "FREQ", "1"},
+			expectedOutput: "",
+			expectedError:  ErrSyntax,
+		},
+		{
+			args:           []string{"OBJECT", "IDLETIME", "copy"},
+			expectedOutput: int64(100),
+		},
+		{
+			args:           []string{"OBJECT", "FREQ", "queue"},
+			expectedOutput: 50,
+		},
+		{
+			args:           []string{"LRANGE", "abs", "0", "-1"},
+			expectedOutput: []string{"a", "b", "c"},
+		},
+		{
+			args:           []string{"GET", "copy"},
+			expectedOutput: "hello",
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.Equal(t, tt.expectedError, err, tt.args)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	entry, _ := db.PeekEntry("queue")
+	assert.True(t, entry.HasExpiryDate)
+	entry, _ = db.PeekEntry("abs")
+	assert.Equal(t, future, strconv.FormatInt(entry.ExpireAt.UnixMilli(), 10))
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpireCommand implements EXPIRE, PEXPIRE, EXPIREAT and PEXPIREAT, which only
+// differ in how the time argument is interpreted.
+type ExpireCommand struct {
+	baseCommand
+	// unit is the SET style option the argument is read as: EX, PX, EXAT or PXAT
+	unit string
+}
+
+func (c *ExpireCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	name := strings.ToUpper(c.GetName())
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+	}
+	key := args[1]
+	number, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return "", ErrNotInteger
+	}
+
+	var nx, xx, gt, lt bool
+	for _, option := range args[3:] {
+		switch strings.ToUpper(option) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "GT":
+			gt = true
+		case "LT":
+			lt = true
+		default:
+			return "", fmt.Errorf("Unsupported option %s", option)
+		}
+	}
+	if nx && (xx || gt || lt) {
+		return "", fmt.Errorf("NX and XX, GT or LT options at the same time are not compatible")
+	}
+	if gt && lt {
+		return "", fmt.Errorf("GT and LT options at the same time are not compatible")
+	}
+
+	expireAt, ok := expiryToUnixMilli(c.unit, number)
+	if !ok {
+		return "", fmt.Errorf("invalid expire time in '%s' command", strings.ToLower(name))
+	}
+
+	entry, ok := c.db.GetEntry(key)
+	if !ok {
+		return 0, nil
+	}
+
+	// a key without a TTL counts as having an infinite one for GT and LT
+	current := entry.ExpireAt.UnixMilli()
+	switch {
+	case nx && entry.HasExpiryDate,
+		xx && !entry.HasExpiryDate,
+		gt && (!entry.HasExpiryDate || expireAt <= current),
+		lt && entry.HasExpiryDate && expireAt >= current:
+		return 0, nil
+	}
+
+	if expireAt <= time.Now().UnixMilli() {
+		c.db.DelValue(key)
+		return 1, nil
+	}
+	entry.HasExpiryDate = true
+	entry.ExpireAt = time.UnixMilli(expireAt)
+	return 1, nil
+}
+
+// TTLCommand implements TTL, PTTL, EXPIRETIME and PEXPIRETIME. Missing keys
+// reply -2 and keys without an expiry reply -1.
+type TTLCommand struct {
+	baseCommand
+	milliseconds bool
+	absolute     bool
+}
+
+func (c *TTLCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(c.GetName()))
+	}
+
+	entry, ok := c.db.PeekEntry(args[1])
+	if !ok {
+		return -2, nil
+	}
+	if !entry.HasExpiryDate {
+		return -1, nil
+	}
+
+	return ttlReply(entry.ExpireAt, c.milliseconds, c.absolute), nil
+}
+
+// ttlReply is what the TTL family replies for an expiry time: the time left or
+// the unix time it happens at, in seconds or milliseconds.
+func ttlReply(expireAt time.Time, milliseconds bool, absolute bool) int64 {
+	at := expireAt.UnixMilli()
+	if absolute {
+		if milliseconds {
+			return at
+		}
+		return at / 1000
+	}
+
+	remaining := max(at-time.Now().UnixMilli(), 0)
+	if milliseconds {
+		return remaining
+	}
+	// round to the nearest second like Redis does
+	return (remaining + 500) / 1000
+}
+
+type PERSISTCommand struct {
+	baseCommand
+}
+
+func (c *PERSISTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'PERSIST' command")
+	}
+
+	entry, ok := c.db.GetEntry(args[1])
+	if !ok || !entry.HasExpiryDate {
+		return 0, nil
+	}
+	entry.HasExpiryDate = false
+	entry.ExpireAt = time.Time{}
+	return 1, nil
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpireCommands(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("foo", "bar")
+	command, _ := NewCommand("RPUSH", db, []string{"RPUSH", "queue", "job"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"TTL", "missing"},
+			expectedOutput: -2,
+		},
+		{
+			args:           []string{"TTL", "foo"},
+			expectedOutput: -1,
+		},
+		{
+			args:           []string{"EXPIRE", "foo", "100", "XX"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"EXPIRE", "foo", "100", "GT"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"EXPIRE", "foo", "100", "NX"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"TTL", "foo"},
+			expectedOutput: int64(100),
+		},
+		{
+			args:           []string{"EXPIRE", "foo", "200", "LT"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"PEXPIRE", "foo", "50000", "LT"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"TTL", "foo"},
+			expectedOutput: int64(50),
+		},
+		{
+			args:           []string{"PERSIST", "foo"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"PERSIST", "foo"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"EXPIREAT", "queue", "4102444800"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXPIRETIME", "queue"},
+			expectedOutput: int64(4102444800),
+		},
+		{
+			args:           []string{"PEXPIRETIME", "queue"},
+			expectedOutput: int64(4102444800000),
+		},
+		{
+			args:           []string{"EXPIRE", "queue", "-1"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXISTS", "queue"},
+			expectedOutput: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	command, _ = NewCommand("EXPIRE", db, []string{"EXPIRE", "foo", "10", "NX", "GT"})
+	_, err = command.ExecuteCommand()
+	assert.Error(t, err)
+}
+
+func TestExpiredListIsRecreated(t *testing.T) {
+	db := db.NewDb()
+	command, _ := NewCommand("RPUSH", db, []string{"RPUSH", "queue", "a", "b"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+
+	db.DbMap["queue"].HasExpiryDate = true
+	db.DbMap["queue"].ExpireAt = time.Now().Add(-time.Second)
+
+	command, _ = NewCommand("RPUSH", db, []string{"RPUSH", "queue", "c"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, output)
+
+	command, _ = NewCommand("LLEN", db, []string{"LLEN", "queue"})
+	output, err = command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, output)
+}
@@ -0,0 +1,94 @@
+package commands
+
+// globMatch reports whether str matches a Redis glob pattern. It supports
+// '*', '?', character classes such as [abc], [^abc] and [a-z], and
+// backslash escapes, following stringmatchlen in Redis.
+func globMatch(pattern string, str string) bool {
+	skipLongerMatches := false
+	return globMatchImpl(pattern, str, &skipLongerMatches)
+}
+
+// globMatchImpl is globMatch with the fix for CVE-2022-36021: once a nested
+// '*' has tried every suffix of str and failed, trying shorter suffixes for
+// an outer '*' cannot succeed either, so skipLongerMatches stops the retries
+// that would otherwise take exponential time.
+func globMatchImpl(pattern string, str string, skipLongerMatches *bool) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(str); i++ {
+				if globMatchImpl(pattern[1:], str[i:], skipLongerMatches) {
+					return true
+				}
+				if *skipLongerMatches {
+					return false
+				}
+			}
+			*skipLongerMatches = true
+			return false
+		case '?':
+			if len(str) == 0 {
+				return false
+			}
+			str = str[1:]
+		case '[':
+			if len(str) == 0 {
+				return false
+			}
+			pattern = pattern[1:]
+			negate := len(pattern) > 0 && pattern[0] == '^'
+			if negate {
+				pattern = pattern[1:]
+			}
+			match := false
+			// an unterminated class runs to the end of the pattern
+			for len(pattern) > 0 && pattern[0] != ']' {
+				if pattern[0] == '\\' && len(pattern) >= 2 {
+					pattern = pattern[1:]
+					if pattern[0] == str[0] {
+						match = true
+					}
+				} else if len(pattern) >= 3 && pattern[1] == '-' {
+					start, end := pattern[0], pattern[2]
+					if start > end {
+						start, end = end, start
+					}
+					if str[0] >= start && str[0] <= end {
+						match = true
+					}
+					pattern = pattern[2:]
+				} else if pattern[0] == str[0] {
+					match = true
+				}
+				pattern = pattern[1:]
+			}
+			if negate {
+				match = !match
+			}
+			if !match {
+				return false
+			}
+			str = str[1:]
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(str) == 0 || pattern[0] != str[0] {
+				return false
+			}
+			str = str[1:]
+		}
+		if len(pattern) > 0 {
+			pattern = pattern[1:]
+		}
+	}
+	return len(str) == 0
+}
@@ -0,0 +1,314 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+)
+
+// getHash returns the entry and hash stored at key. A missing key gives a nil
+// entry and no error, a key holding another type gives ErrWrongType.
+func getHash(store *db.Db, key string) (*db.MapValue, map[string]string, error) {
+	entry, ok := store.GetEntry(key)
+	if !ok {
+		return nil, nil, nil
+	}
+	hash, ok := entry.Value.(map[string]string)
+	if !ok {
+		return nil, nil, ErrWrongType
+	}
+	return entry, hash, nil
+}
+
+// getOrCreateHash is getHash for writes, storing an empty hash at key when it
+// is missing.
+func getOrCreateHash(store *db.Db, key string) (*db.MapValue, map[string]string, error) {
+	entry, hash, err := getHash(store, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if entry == nil {
+		hash = make(map[string]string)
+		entry = &db.MapValue{Value: hash}
+		store.SetEntry(key, entry)
+	}
+	return entry, hash, nil
+}
+
+type HSETCommand struct {
+	baseCommand
+}
+
+func (c *HSETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 4 || len(args)%2 != 0 {
+		return "", fmt.Errorf("wrong number of arguments for 'HSET' command")
+	}
+
+	entry, hash, err := getOrCreateHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	added := 0
+	for i := 2; i < len(args); i += 2 {
+		if _, exists := hash[args[i]]; !exists {
+			added++
+		}
+		// overwriting a field drops its TTL
+		hash[args[i]] = args[i+1]
+		entry.PersistField(args[i])
+	}
+	return added, nil
+}
+
+type HSETNXCommand struct {
+	baseCommand
+}
+
+func (c *HSETNXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'HSETNX' command")
+	}
+
+	_, hash, err := getOrCreateHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if _, exists := hash[args[2]]; exists {
+		return 0, nil
+	}
+	hash[args[2]] = args[3]
+	return 1, nil
+}
+
+type HGETCommand struct {
+	baseCommand
+}
+
+func (c *HGETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'HGET' command")
+	}
+
+	_, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	value, ok := hash[args[2]]
+	if !ok {
+		return nil, nil
+	}
+	return value, nil
+}
+
+type HMGETCommand struct {
+	baseCommand
+}
+
+func (c *HMGETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'HMGET' command")
+	}
+
+	_, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	result := make([]any, 0, len(args)-2)
+	for _, field := range args[2:] {
+		value, ok := hash[field]
+		if !ok {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+type HDELCommand struct {
+	baseCommand
+}
+
+func (c *HDELCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'HDEL' command")
+	}
+
+	entry, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return 0, nil
+	}
+	removed := 0
+	for _, field := range args[2:] {
+		if _, exists := hash[field]; exists {
+			delete(hash, field)
+			entry.PersistField(field)
+			removed++
+		}
+	}
+	if len(hash) == 0 {
+		c.db.DelValue(args[1])
+	}
+	return removed, nil
+}
+
+type HLENCommand struct {
+	baseCommand
+}
+
+func (c *HLENCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'HLEN' command")
+	}
+
+	_, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	return len(hash), nil
+}
+
+type HEXISTSCommand struct {
+	baseCommand
+}
+
+func (c *HEXISTSCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'HEXISTS' command")
+	}
+
+	_, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if _, exists := hash[args[2]]; exists {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+type HSTRLENCommand struct {
+	baseCommand
+}
+
+func (c *HSTRLENCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'HSTRLEN' command")
+	}
+
+	_, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	return len(hash[args[2]]), nil
+}
+
+// HGETALLCommand implements HGETALL, HKEYS and HVALS, which reply the fields,
+// the values or both of a hash.
+type HGETALLCommand struct {
+	baseCommand
+	fields bool
+	values bool
+}
+
+func (c *HGETALLCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(c.GetName()))
+	}
+
+	_, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	result := []string{}
+	for field, value := range hash {
+		if c.fields {
+			result = append(result, field)
+		}
+		if c.values {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+type HINCRBYCommand struct {
+	baseCommand
+}
+
+func (c *HINCRBYCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'HINCRBY' command")
+	}
+	delta, ok := parseInteger(args[3])
+	if !ok {
+		return "", ErrNotInteger
+	}
+
+	_, hash, err := getOrCreateHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	var current int64
+	if value, exists := hash[args[2]]; exists {
+		if current, ok = parseInteger(value); !ok {
+			return "", fmt.Errorf("hash value is not an integer")
+		}
+	}
+	if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+		return "", ErrOverflow
+	}
+	current += delta
+	hash[args[2]] = strconv.FormatInt(current, 10)
+	return current, nil
+}
+
+type HINCRBYFLOATCommand struct {
+	baseCommand
+}
+
+func (c *HINCRBYFLOATCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'HINCRBYFLOAT' command")
+	}
+	increment, err := parseLongDouble(args[3])
+	if err != nil {
+		return "", err
+	}
+
+	_, hash, err := getOrCreateHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	current := new(big.Float)
+	if value, exists := hash[args[2]]; exists {
+		current, err = parseLongDouble(value)
+		if err != nil {
+			return "", fmt.Errorf("hash value is not a float")
+		}
+	}
+	result, err := addLongDouble(current, increment)
+	if err != nil {
+		return "", err
+	}
+	hash[args[2]] = result
+	return result, nil
+}
@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"HSET", "user:1", "name", "ann", "age", "30"},
+			expectedOutput: 2,
+		},
+		{
+			args:           []string{"HSET", "user:1", "name", "anne", "city", "oslo"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"TYPE", "user:1"},
+			expectedOutput: "hash",
+		},
+		{
+			args:           []string{"HGET", "user:1", "name"},
+			expectedOutput: "anne",
+		},
+		{
+			args:           []string{"HGET", "user:1", "missing"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"HMGET", "user:1", "age", "missing", "city"},
+			expectedOutput: []any{"30", nil, "oslo"},
+		},
+		{
+			args:           []string{"HLEN", "user:1"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"HEXISTS", "user:1", "city"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HSTRLEN", "user:1", "name"},
+			expectedOutput: 4,
+		},
+		{
+			args:           []string{"HSETNX", "user:1", "name", "bob"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HSETNX", "user:1", "email", "a@b.c"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HINCRBY", "user:1", "age", "-5"},
+			expectedOutput: int64(25),
+		},
+		{
+			args:           []string{"HINCRBY", "user:1", "visits", "1"},
+			expectedOutput: int64(1),
+		},
+		{
+			args:           []string{"HINCRBYFLOAT", "user:1", "score", "10.5"},
+			expectedOutput: "10.5",
+		},
+		{
+			args:           []string{"HINCRBYFLOAT", "user:1", "score", "-0.25"},
+			expectedOutput: "10.25",
+		},
+		{
+			args:           []string{"HINCRBYFLOAT", "ratios", "ratio", "0.1"},
+			expectedOutput: "0.1",
+		},
+		{
+			args:           []string{"HINCRBYFLOAT", "ratios", "ratio", "0.2"},
+			expectedOutput: "0.3",
+		},
+		{
+			args:           []string{"HDEL", "user:1", "email", "visits", "score", "missing"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "user:1"},
+			expectedOutput: "listpack",
+		},
+		{
+			args:           []string{"HDEL", "user:1", "name", "age", "city"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"EXISTS", "user:1"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HGETALL", "user:1"},
+			expectedOutput: []string{},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	db.SetValue("user:2", map[string]string{"name": "bob", "age": "41"})
+	for _, tt := range []struct {
+		command  string
+		expected []string
+	}{
+		{"HGETALL", []string{"name", "bob", "age", "41"}},
+		{"HKEYS", []string{"name", "age"}},
+		{"HVALS", []string{"bob", "41"}},
+	} {
+		command, _ := NewCommand(tt.command, db, []string{tt.command, "user:2"})
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, tt.expected, output, tt.command)
+	}
+
+	db.SetValue("name", "value")
+	db.SetValue("padded", map[string]string{"number": "007"})
+	for _, args := range [][]string{
+		{"HSET", "name", "field", "value"},
+		{"HGET", "name", "field"},
+		{"HSET", "user:2", "field"},
+		{"HINCRBY", "user:2", "name", "1"},
+		{"HINCRBY", "user:2", "age", "+1"},
+		{"HINCRBY", "padded", "number", "1"},
+		{"HINCRBYFLOAT", "user:2", "name", "1"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+}
+
+func TestHashRepliesWithMinusOne(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("only", map[string]string{"-1": "-1"})
+
+	for _, args := range [][]string{
+		{"HKEYS", "only"},
+		{"HVALS", "only"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("*1\r\n$2\r\n-1\r\n"), SerializeOutput(args[0], output, false), args)
+	}
+	db.SetValue("only", map[string]string{"-1": ""})
+	command, _ := NewCommand("HGETALL", db, []string{"HGETALL", "only"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("*2\r\n$2\r\n-1\r\n$0\r\n\r\n"), SerializeOutput("HGETALL", output, false))
+}
+
+func TestHashesWithOtherCommands(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("user:1", map[string]string{"name": "ann", "rank": "2"})
+	db.SetValue("user:2", map[string]string{"name": "bob", "rank": "1"})
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"RPUSH", "users", "1", "2"},
+			expectedOutput: 2,
+		},
+		{
+			args:           []string{"SORT", "users", "BY", "user:*->rank", "GET", "user:*->name"},
+			expectedOutput: []any{"bob", "ann"},
+		},
+		{
+			args:           []string{"COPY", "user:1", "copy"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HSET", "copy", "name", "changed"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HGET", "user:1", "name"},
+			expectedOutput: "ann",
+		},
+		{
+			args:           []string{"SCAN", "0", "TYPE", "hash", "MATCH", "user:*"},
+			expectedOutput: []any{"0", []string{"user:1", "user:2"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		if scan, ok := output.([]any); ok && tt.args[0] == "SCAN" {
+			assert.ElementsMatch(t, tt.expectedOutput.([]any)[1], scan[1])
+			continue
+		}
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	dump, _ := NewCommand("DUMP", db, []string{"DUMP", "user:2"})
+	payload, err := dump.ExecuteCommand()
+	assert.NoError(t, err)
+	restore, _ := NewCommand("RESTORE", db, []string{"RESTORE", "restored", "0", payload.(string)})
+	_, err = restore.ExecuteCommand()
+	assert.NoError(t, err)
+	value, _ := db.GetValue("restored")
+	assert.Equal(t, map[string]string{"name": "bob", "rank": "1"}, value)
+}
@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFieldExpire is the latest unix time, in milliseconds, a hash field can be
+// set to expire at, like Redis' EB_EXPIRE_TIME_MAX.
+const maxFieldExpire = 1<<48 - 1
+
+// parseFields reads the "FIELDS numfields ..." block that ends the hash field
+// expiry commands, where each field takes perField arguments, and returns the
+// arguments following numfields.
+func parseFields(args []string, perField int) ([]string, error) {
+	if len(args) < 2 || !strings.EqualFold(args[0], "FIELDS") {
+		return nil, fmt.Errorf("Mandatory argument FIELDS is missing or not at the right position")
+	}
+	numFields, err := strconv.Atoi(args[1])
+	if err != nil {
+		return nil, ErrNotInteger
+	}
+	if numFields <= 0 {
+		return nil, fmt.Errorf("Parameter `numFields` should be greater than 0")
+	}
+	if numFields > (len(args)-2)/perField || numFields*perField != len(args)-2 {
+		return nil, fmt.Errorf("The `numfields` parameter must match the number of arguments")
+	}
+	return args[2:], nil
+}
+
+// parseFieldExpiry is parseExpiry for hash fields, which cannot expire later
+// than maxFieldExpire.
+func parseFieldExpiry(option string, arg string, commandName string) (time.Time, error) {
+	expireAt, err := parseExpiry(option, arg, commandName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if expireAt.UnixMilli() > maxFieldExpire {
+		return time.Time{}, fmt.Errorf("invalid expire time in '%s' command", commandName)
+	}
+	return expireAt, nil
+}
+
+// HEXPIRECommand implements HEXPIRE, HPEXPIRE, HEXPIREAT and HPEXPIREAT. Each
+// field replies -2 when it does not exist, 0 when the NX, XX, GT or LT
+// condition was not met, 2 when the time has already passed and the field
+// was deleted, and 1 when its expiry was set.
+type HEXPIRECommand struct {
+	baseCommand
+	// unit is the SET style option the argument is read as: EX, PX, EXAT or PXAT
+	unit string
+}
+
+func (c *HEXPIRECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	name := strings.ToLower(c.GetName())
+	if len(args) < 6 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(name))
+	}
+	key := args[1]
+	number, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	if number < 0 {
+		return "", fmt.Errorf("invalid expire time, must be >= 0")
+	}
+	expireAt, ok := expiryToUnixMilli(c.unit, number)
+	if !ok || expireAt > maxFieldExpire {
+		return "", fmt.Errorf("invalid expire time in '%s' command", name)
+	}
+
+	rest := args[3:]
+	condition := strings.ToUpper(rest[0])
+	switch condition {
+	case "NX", "XX", "GT", "LT":
+		rest = rest[1:]
+	default:
+		condition = ""
+	}
+	fields, err := parseFields(rest, 1)
+	if err != nil {
+		return "", err
+	}
+
+	entry, hash, err := getHash(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	result := make([]any, len(fields))
+	now := time.Now().UnixMilli()
+	for i, field := range fields {
+		if _, exists := hash[field]; !exists {
+			result[i] = -2
+			continue
+		}
+		// a field without a TTL counts as having an infinite one for GT and LT
+		current, hasTTL := entry.FieldExpireAt[field]
+		switch {
+		case condition == "NX" && hasTTL,
+			condition == "XX" && !hasTTL,
+			condition == "GT" && (!hasTTL || expireAt <= current.UnixMilli()),
+			condition == "LT" && hasTTL && expireAt >= current.UnixMilli():
+			result[i] = 0
+			continue
+		}
+		if expireAt <= now {
+			delete(hash, field)
+			entry.PersistField(field)
+			result[i] = 2
+			continue
+		}
+		entry.SetFieldExpiry(field, time.UnixMilli(expireAt))
+		result[i] = 1
+	}
+	if entry != nil && len(hash) == 0 {
+		c.db.DelValue(key)
+	}
+	return result, nil
+}
+
+// HTTLCommand implements HTTL, HPTTL, HEXPIRETIME and HPEXPIRETIME. Each field
+// replies like TTL does for a key: -2 when it does not exist and -1 when it
+// has no expiry.
+type HTTLCommand struct {
+	baseCommand
+	milliseconds bool
+	absolute     bool
+}
+
+func (c *HTTLCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 5 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", strings.ToUpper(c.GetName()))
+	}
+	fields, err := parseFields(args[2:], 1)
+	if err != nil {
+		return "", err
+	}
+
+	entry, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	result := make([]any, len(fields))
+	for i, field := range fields {
+		if _, exists := hash[field]; !exists {
+			result[i] = -2
+			continue
+		}
+		expireAt, hasTTL := entry.FieldExpireAt[field]
+		if !hasTTL {
+			result[i] = -1
+			continue
+		}
+		result[i] = ttlReply(expireAt, c.milliseconds, c.absolute)
+	}
+	return result, nil
+}
+
+// HPERSISTCommand removes the expiry of hash fields. Each field replies -2
+// when it does not exist, -1 when it has no expiry and 1 when it was removed.
+type HPERSISTCommand struct {
+	baseCommand
+}
+
+func (c *HPERSISTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 5 {
+		return "", fmt.Errorf("wrong number of arguments for 'HPERSIST' command")
+	}
+	fields, err := parseFields(args[2:], 1)
+	if err != nil {
+		return "", err
+	}
+
+	entry, hash, err := getHash(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	result := make([]any, len(fields))
+	for i, field := range fields {
+		switch _, exists := hash[field]; {
+		case !exists:
+			result[i] = -2
+		case entry.PersistField(field):
+			result[i] = 1
+		default:
+			result[i] = -1
+		}
+	}
+	return result, nil
+}
+
+// HGETEXCommand is HMGET that also sets or, with PERSIST, removes the expiry
+// of the fields it returns.
+type HGETEXCommand struct {
+	baseCommand
+}
+
+func (c *HGETEXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 5 {
+		return "", fmt.Errorf("wrong number of arguments for 'HGETEX' command")
+	}
+	key := args[1]
+
+	rest := args[2:]
+	var expiryOption string
+	var persist bool
+	var expireAt time.Time
+	switch option := strings.ToUpper(rest[0]); option {
+	case "PERSIST":
+		persist = true
+		rest = rest[1:]
+	case "EX", "PX", "EXAT", "PXAT":
+		if len(rest) < 2 {
+			return "", ErrSyntax
+		}
+		var err error
+		expireAt, err = parseFieldExpiry(option, rest[1], "hgetex")
+		if err != nil {
+			return "", err
+		}
+		expiryOption = option
+		rest = rest[2:]
+	}
+	fields, err := parseFields(rest, 1)
+	if err != nil {
+		return "", err
+	}
+
+	entry, hash, err := getHash(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	result := make([]any, len(fields))
+	now := time.Now()
+	for i, field := range fields {
+		value, exists := hash[field]
+		if !exists {
+			continue
+		}
+		result[i] = value
+		switch {
+		case persist:
+			entry.PersistField(field)
+		case expiryOption != "" && !expireAt.After(now):
+			// an absolute time that already passed deletes the field
+			delete(hash, field)
+			entry.PersistField(field)
+		case expiryOption != "":
+			entry.SetFieldExpiry(field, expireAt)
+		}
+	}
+	if entry != nil && len(hash) == 0 {
+		c.db.DelValue(key)
+	}
+	return result, nil
+}
+
+// HSETEXCommand is HSET that also sets the expiry of the fields it writes.
+// FNX only sets the fields when none of them exist and FXX only when all of
+// them do. Without an expiry or KEEPTTL the fields lose their TTL, as with
+// HSET.
+type HSETEXCommand struct {
+	baseCommand
+}
+
+func (c *HSETEXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 6 {
+		return "", fmt.Errorf("wrong number of arguments for 'HSETEX' command")
+	}
+	key := args[1]
+
+	var condition, expiryOption string
+	var keepTTL bool
+	var expireAt time.Time
+	i := 2
+options:
+	for ; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		switch option {
+		case "FNX", "FXX":
+			if condition != "" {
+				return "", ErrSyntax
+			}
+			condition = option
+		case "KEEPTTL":
+			if keepTTL || expiryOption != "" {
+				return "", ErrSyntax
+			}
+			keepTTL = true
+		case "EX", "PX", "EXAT", "PXAT":
+			if keepTTL || expiryOption != "" || i+1 >= len(args) {
+				return "", ErrSyntax
+			}
+			var err error
+			expireAt, err = parseFieldExpiry(option, args[i+1], "hsetex")
+			if err != nil {
+				return "", err
+			}
+			expiryOption = option
+			i++
+		default:
+			break options
+		}
+	}
+	fields, err := parseFields(args[i:], 2)
+	if err != nil {
+		return "", err
+	}
+
+	_, hash, err := getHash(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	for j := 0; j < len(fields); j += 2 {
+		_, exists := hash[fields[j]]
+		if (condition == "FNX" && exists) || (condition == "FXX" && !exists) {
+			return 0, nil
+		}
+	}
+
+	entry, hash, err := getOrCreateHash(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	expired := expiryOption != "" && !expireAt.After(time.Now())
+	for j := 0; j < len(fields); j += 2 {
+		field := fields[j]
+		switch {
+		case expired:
+			// an absolute time that already passed deletes the field
+			delete(hash, field)
+			entry.PersistField(field)
+			continue
+		case expiryOption != "":
+			entry.SetFieldExpiry(field, expireAt)
+		case !keepTTL:
+			entry.PersistField(field)
+		}
+		hash[field] = fields[j+1]
+	}
+	if len(hash) == 0 {
+		c.db.DelValue(key)
+	}
+	return 1, nil
+}
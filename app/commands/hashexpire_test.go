@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashFieldExpireCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"HSET", "sessions", "phone", "t1", "laptop", "t2", "tablet", "t3"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"HEXPIRE", "sessions", "100", "FIELDS", "2", "phone", "missing"},
+			expectedOutput: []any{1, -2},
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "3", "phone", "laptop", "missing"},
+			expectedOutput: []any{int64(100), -1, -2},
+		},
+		{
+			args:           []string{"HEXPIRE", "sessions", "200", "NX", "FIELDS", "2", "phone", "laptop"},
+			expectedOutput: []any{0, 1},
+		},
+		{
+			args:           []string{"HPEXPIRE", "sessions", "50000", "XX", "FIELDS", "2", "phone", "tablet"},
+			expectedOutput: []any{1, 0},
+		},
+		{
+			args:           []string{"HPTTL", "sessions", "FIELDS", "1", "tablet"},
+			expectedOutput: []any{-1},
+		},
+		{
+			args:           []string{"HEXPIRE", "sessions", "10", "GT", "FIELDS", "3", "phone", "laptop", "tablet"},
+			expectedOutput: []any{0, 0, 0},
+		},
+		{
+			args:           []string{"HEXPIRE", "sessions", "10", "LT", "FIELDS", "3", "phone", "laptop", "tablet"},
+			expectedOutput: []any{1, 1, 1},
+		},
+		{
+			args:           []string{"HEXPIREAT", "sessions", "4102444800", "FIELDS", "1", "phone"},
+			expectedOutput: []any{1},
+		},
+		{
+			args:           []string{"HEXPIRETIME", "sessions", "FIELDS", "1", "phone"},
+			expectedOutput: []any{int64(4102444800)},
+		},
+		{
+			args:           []string{"HPEXPIRETIME", "sessions", "FIELDS", "1", "phone"},
+			expectedOutput: []any{int64(4102444800000)},
+		},
+		{
+			args:           []string{"HPERSIST", "sessions", "FIELDS", "3", "phone", "missing", "phone"},
+			expectedOutput: []any{1, -2, -1},
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "sessions"},
+			expectedOutput: "listpackex",
+		},
+		{
+			// overwriting a field drops its TTL
+			args:           []string{"HSET", "sessions", "laptop", "t4"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "2", "laptop", "tablet"},
+			expectedOutput: []any{-1, int64(10)},
+		},
+		{
+			args:           []string{"HINCRBY", "sessions", "counter", "1"},
+			expectedOutput: int64(1),
+		},
+		{
+			args:           []string{"HEXPIRE", "sessions", "0", "FIELDS", "2", "counter", "missing"},
+			expectedOutput: []any{2, -2},
+		},
+		{
+			args:           []string{"HLEN", "sessions"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"HGETEX", "sessions", "EX", "30", "FIELDS", "2", "laptop", "missing"},
+			expectedOutput: []any{"t4", nil},
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "1", "laptop"},
+			expectedOutput: []any{int64(30)},
+		},
+		{
+			args:           []string{"HGETEX", "sessions", "PERSIST", "FIELDS", "1", "laptop"},
+			expectedOutput: []any{"t4"},
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "1", "laptop"},
+			expectedOutput: []any{-1},
+		},
+		{
+			args:           []string{"HGETEX", "sessions", "PXAT", "1", "FIELDS", "1", "phone"},
+			expectedOutput: []any{"t1"},
+		},
+		{
+			args:           []string{"HEXISTS", "sessions", "phone"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HSETEX", "sessions", "FNX", "EX", "60", "FIELDS", "2", "watch", "t5", "laptop", "t6"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HSETEX", "sessions", "FNX", "EX", "60", "FIELDS", "1", "watch", "t5"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HSETEX", "sessions", "FXX", "KEEPTTL", "FIELDS", "2", "watch", "t6", "tablet", "t7"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "2", "watch", "tablet"},
+			expectedOutput: []any{int64(60), int64(10)},
+		},
+		{
+			args:           []string{"HSETEX", "sessions", "FIELDS", "1", "watch", "t8"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HMGET", "sessions", "watch", "tablet"},
+			expectedOutput: []any{"t8", "t7"},
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "1", "watch"},
+			expectedOutput: []any{-1},
+		},
+		{
+			args:           []string{"HSETEX", "sessions", "FXX", "FIELDS", "1", "missing", "x"},
+			expectedOutput: 0,
+		},
+		{
+			// the key goes away with its last field
+			args:           []string{"HPEXPIREAT", "sessions", "1", "FIELDS", "3", "laptop", "tablet", "watch"},
+			expectedOutput: []any{2, 2, 2},
+		},
+		{
+			args:           []string{"EXISTS", "sessions"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"HEXPIRE", "sessions", "100", "FIELDS", "1", "phone"},
+			expectedOutput: []any{-2},
+		},
+		{
+			args:           []string{"HTTL", "sessions", "FIELDS", "1", "phone"},
+			expectedOutput: []any{-2},
+		},
+		{
+			args:           []string{"HGETEX", "sessions", "EX", "10", "FIELDS", "1", "phone"},
+			expectedOutput: []any{nil},
+		},
+		{
+			args:           []string{"HSETEX", "tokens", "EXAT", "1", "FIELDS", "1", "old", "x"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXISTS", "tokens"},
+			expectedOutput: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	db.SetValue("name", "value")
+	for _, args := range [][]string{
+		{"HEXPIRE", "name", "10", "FIELDS", "1", "f"},
+		{"HTTL", "name", "FIELDS", "1", "f"},
+		{"HEXPIRE", "hash", "-1", "FIELDS", "1", "f"},
+		{"HEXPIRE", "hash", "10", "FIELDS", "0", "f"},
+		{"HEXPIRE", "hash", "10", "FIELDS", "2", "f"},
+		{"HEXPIRE", "hash", "10", "NX", "XX", "FIELDS", "1", "f"},
+		{"HEXPIRE", "hash", "10", "FIELD", "1", "f"},
+		{"HPEXPIREAT", "hash", "281474976710656", "FIELDS", "1", "f"},
+		{"HPERSIST", "hash", "FIELDS", "x", "f"},
+		{"HGETEX", "hash", "EX", "0", "FIELDS", "1", "f"},
+		{"HGETEX", "hash", "EX", "10", "PERSIST", "FIELDS", "1", "f"},
+		{"HSETEX", "hash", "FIELDS", "1", "f"},
+		{"HSETEX", "hash", "FNX", "FXX", "FIELDS", "1", "f", "v"},
+		{"HSETEX", "hash", "KEEPTTL", "EX", "10", "FIELDS", "1", "f", "v"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+}
+
+func TestExpiredHashFields(t *testing.T) {
+	store := db.NewDb()
+	entry := &db.MapValue{Value: map[string]string{"phone": "t1", "laptop": "t2"}}
+	store.SetEntry("sessions", entry)
+	entry.SetFieldExpiry("phone", time.Now().Add(-time.Second))
+	entry.SetFieldExpiry("laptop", time.Now().Add(time.Hour))
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"HGETALL", "sessions"},
+			expectedOutput: []string{"laptop", "t2"},
+		},
+		{
+			args:           []string{"COPY", "sessions", "copy"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"HTTL", "copy", "FIELDS", "1", "laptop"},
+			expectedOutput: []any{int64(3600)},
+		},
+	}
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], store, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	dump, _ := NewCommand("DUMP", store, []string{"DUMP", "sessions"})
+	payload, err := dump.ExecuteCommand()
+	assert.NoError(t, err)
+	restore, _ := NewCommand("RESTORE", store, []string{"RESTORE", "restored", "0", payload.(string)})
+	_, err = restore.ExecuteCommand()
+	assert.NoError(t, err)
+	restored, ok := store.GetEntry("restored")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"laptop": "t2"}, restored.Value)
+	assert.Equal(t, entry.FieldExpireAt["laptop"].UnixMilli(), restored.FieldExpireAt["laptop"].UnixMilli())
+
+	// the key is deleted once its last field expires
+	entry.SetFieldExpiry("laptop", time.Now().Add(-time.Second))
+	exists, _ := NewCommand("EXISTS", store, []string{"EXISTS", "sessions"})
+	output, err := exists.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, output)
+}
@@ -0,0 +1,283 @@
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+)
+
+// deleteKeys removes every live key in keys and returns how many were deleted.
+func deleteKeys(store *db.Db, keys []string) int {
+	deleted := 0
+	for _, key := range keys {
+		if _, ok := store.GetEntry(key); !ok {
+			continue
+		}
+		store.DelValue(key)
+		deleted++
+	}
+	return deleted
+}
+
+type DELCommand struct {
+	baseCommand
+}
+
+func (c *DELCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'DEL' command")
+	}
+	return deleteKeys(c.db, args[1:]), nil
+}
+
+type UNLINKCommand struct {
+	baseCommand
+}
+
+// UNLINK frees memory in the background in Redis; here values are released by
+// the garbage collector either way, so it behaves exactly like DEL.
+func (c *UNLINKCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'UNLINK' command")
+	}
+	return deleteKeys(c.db, args[1:]), nil
+}
+
+type EXISTSCommand struct {
+	baseCommand
+}
+
+func (c *EXISTSCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'EXISTS' command")
+	}
+	// a key repeated in the arguments is counted once per occurrence
+	count := 0
+	for _, key := range args[1:] {
+		if _, ok := c.db.PeekEntry(key); ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type KEYSCommand struct {
+	baseCommand
+}
+
+func (c *KEYSCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'KEYS' command")
+	}
+	pattern := args[1]
+
+	keys := []string{}
+	for mapKey := range c.db.DbMap {
+		key := mapKey.(string)
+		if _, ok := c.db.PeekEntry(key); !ok {
+			continue
+		}
+		if globMatch(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+type SCANCommand struct {
+	baseCommand
+}
+
+func (c *SCANCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'SCAN' command")
+	}
+	cursor, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor")
+	}
+
+	pattern, typeFilter := "", ""
+	count := 10
+	for i := 2; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return "", ErrSyntax
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			count, err = strconv.Atoi(args[i+1])
+			if err != nil {
+				return "", ErrNotInteger
+			}
+			if count < 1 {
+				return "", ErrSyntax
+			}
+		case "TYPE":
+			typeFilter = strings.ToLower(args[i+1])
+		default:
+			return "", ErrSyntax
+		}
+		i++
+	}
+
+	batch, nextCursor := c.db.Scan(cursor, count)
+
+	// like Redis, MATCH and TYPE filter the batch after it was picked, so a
+	// call can return fewer than COUNT keys while the scan is not finished
+	keys := []string{}
+	for _, key := range batch {
+		entry, ok := c.db.PeekEntry(key)
+		if !ok {
+			continue
+		}
+		if pattern != "" && !globMatch(pattern, key) {
+			continue
+		}
+		if typeFilter != "" {
+			name, err := typeName(entry.Value)
+			if err != nil || name != typeFilter {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	return []any{strconv.FormatUint(nextCursor, 10), keys}, nil
+}
+
+// copyValue returns a copy of val that shares no mutable state with it.
+func copyValue(val any) any {
+	switch v := val.(type) {
+	case *quicklist.List:
+		return v.Clone()
+	case map[string]string:
+		return maps.Clone(v)
+	default:
+		return v
+	}
+}
+
+// signalListReady serves the clients blocked on key once it holds a list.
+func signalListReady(store *db.Db, key string) {
+	entry, ok := store.DbMap[key]
+	if !ok {
+		return
+	}
+	if _, isList := entry.Value.(*quicklist.List); !isList {
+		return
+	}
+	store.ServeWaiters(key)
+}
+
+func renameKey(store *db.Db, source string, destination string, entry *db.MapValue) {
+	store.DelValue(source)
+	store.PutEntry(destination, entry)
+	signalListReady(store, destination)
+}
+
+type RENAMECommand struct {
+	baseCommand
+}
+
+func (c *RENAMECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'RENAME' command")
+	}
+	source, destination := args[1], args[2]
+
+	entry, ok := c.db.GetEntry(source)
+	if !ok {
+		return "", fmt.Errorf("no such key")
+	}
+	if source != destination {
+		renameKey(c.db, source, destination, entry)
+	}
+	return "OK", nil
+}
+
+type RENAMENXCommand struct {
+	baseCommand
+}
+
+func (c *RENAMENXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'RENAMENX' command")
+	}
+	source, destination := args[1], args[2]
+
+	entry, ok := c.db.GetEntry(source)
+	if !ok {
+		return "", fmt.Errorf("no such key")
+	}
+	if _, exists := c.db.GetEntry(destination); exists {
+		return 0, nil
+	}
+	renameKey(c.db, source, destination, entry)
+	return 1, nil
+}
+
+type COPYCommand struct {
+	baseCommand
+}
+
+func (c *COPYCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'COPY' command")
+	}
+	source, destination := args[1], args[2]
+
+	replace := false
+	target := c.db
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "REPLACE":
+			replace = true
+		case "DB":
+			if i+1 >= len(args) {
+				return "", ErrSyntax
+			}
+			var err error
+			target, err = siblingDb(c.db, args[i+1])
+			if err != nil {
+				return "", err
+			}
+			i++
+		default:
+			return "", ErrSyntax
+		}
+	}
+	if source == destination && target == c.db {
+		return "", fmt.Errorf("source and destination objects are the same")
+	}
+
+	entry, ok := c.db.GetEntry(source)
+	if !ok {
+		return 0, nil
+	}
+	if _, exists := target.GetEntry(destination); exists && !replace {
+		return 0, nil
+	}
+
+	target.SetEntry(destination, &db.MapValue{
+		Value:         copyValue(entry.Value),
+		SetAt:         entry.SetAt,
+		HasExpiryDate: entry.HasExpiryDate,
+		ExpireAt:      entry.ExpireAt,
+		FieldExpireAt: maps.Clone(entry.FieldExpireAt),
+	})
+	signalListReady(target, destination)
+	return 1, nil
+}
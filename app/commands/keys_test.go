@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelAndExistsCommands(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("a", "1")
+	db.SetValue("b", "2")
+	db.SetValue("c", "3")
+	command, _ := NewCommand("RPUSH", db, []string{"RPUSH", "list", "x"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"EXISTS", "a", "a", "missing", "list"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"DEL", "a", "missing", "list"},
+			expectedOutput: 2,
+		},
+		{
+			args:           []string{"UNLINK", "b", "b"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXISTS", "a", "b", "c", "list"},
+			expectedOutput: 1,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		str     string
+		match   bool
+	}{
+		{"*", "anything", true},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h*llo", "heeeello", true},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{"h[a-b]llo", "hcllo", false},
+		{"user:\\*", "user:*", true},
+		{"user:\\*", "user:1", false},
+		{"[\\]]", "]", true},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+		{"*a*b", "xaxab", true},
+		{"*a*a*b", "aaaaab", true},
+		// would backtrack exponentially without skipping longer matches
+		{"*a*a*a*a*a*a*a*a*a*a*a*a*b", strings.Repeat("a", 40), false},
+	}
+
+	for _, tt := range testCases {
+		assert.Equal(t, tt.match, globMatch(tt.pattern, tt.str), tt.pattern+" "+tt.str)
+	}
+}
+
+func TestKeysCommand(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("user:1", "a")
+	db.SetValue("user:2", "b")
+	db.SetValue("session:1", "c")
+
+	command, _ := NewCommand("KEYS", db, []string{"KEYS", "user:*"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user:1", "user:2"}, output)
+}
+
+func TestKeysReplyWithKeyNamedMinusOne(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("-1", "a")
+
+	command, _ := NewCommand("KEYS", db, []string{"KEYS", "*"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("*1\r\n$2\r\n-1\r\n"), SerializeOutput("KEYS", output, false))
+}
+
+func TestScanCommand(t *testing.T) {
+	db := db.NewDb()
+	for i := 0; i < 100; i++ {
+		db.SetValue("key:"+strconv.Itoa(i), "x")
+	}
+	db.SetValue("list", quicklist.New("a"))
+
+	seen := map[string]int{}
+	cursor := "0"
+	for i := 0; ; i++ {
+		command, _ := NewCommand("SCAN", db, []string{"SCAN", cursor, "COUNT", "7", "MATCH", "key:*", "TYPE", "string"})
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		reply := output.([]any)
+		for _, key := range reply[1].([]string) {
+			seen[key]++
+		}
+		// churn the keyspace between calls; none of the original keys may be missed
+		db.SetValue("extra:"+strconv.Itoa(i), "x")
+		db.DelValue("extra:" + strconv.Itoa(i-1))
+
+		cursor = reply[0].(string)
+		if cursor == "0" {
+			break
+		}
+	}
+
+	assert.Equal(t, 100, len(seen))
+	for key, times := range seen {
+		assert.Equal(t, 1, times, key)
+	}
+}
+
+func TestRenameAndCopyCommands(t *testing.T) {
+	db := db.NewDb()
+	command, _ := NewCommand("SET", db, []string{"SET", "config:next", "v2", "PX", "100000"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	db.SetValue("config:current", "v1")
+	db.SetValue("queue", quicklist.New("a", "b"))
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			args:           []string{"RENAMENX", "config:next", "config:current"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"RENAME", "config:next", "config:current"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"RENAME", "config:next", "config:current"},
+			expectedOutput: "",
+			expectedError:  errors.New("no such key"),
+		},
+		{
+			args:           []string{"COPY", "queue", "backup"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"COPY", "queue", "backup"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"COPY", "config:current", "backup", "DB", "0", "REPLACE"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"COPY", "queue", "other", "DB", "3"},
+			expectedOutput: "",
+			expectedError:  errors.New("DB index is out of range"),
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.Equal(t, tt.expectedError, err, tt.args)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	entry, ok := db.GetEntry("config:current")
+	assert.True(t, ok)
+	assert.Equal(t, "v2", entry.Value)
+	assert.True(t, entry.HasExpiryDate)
+	backup, _ := db.GetEntry("backup")
+	assert.True(t, backup.HasExpiryDate)
+}
+
+func TestCopyDoesNotShareLists(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("queue", quicklist.New("a", "b"))
+	command, _ := NewCommand("COPY", db, []string{"COPY", "queue", "backup"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+
+	command, _ = NewCommand("RPUSH", db, []string{"RPUSH", "queue", "c"})
+	_, err = command.ExecuteCommand()
+	assert.NoError(t, err)
+	db.DbMap["queue"].Value.(*quicklist.List).Set(0, "changed")
+
+	backup, _ := db.GetValue("backup")
+	assert.Equal(t, []string{"a", "b"}, backup.(*quicklist.List).Slice())
+}
+
+func TestRenameWakesBlockedClients(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("queue", quicklist.New("a"))
+	blocked := blockClient(t, db, "BLPOP", "jobs", "0")
+
+	command, _ := NewCommand("RENAME", db, []string{"RENAME", "queue", "jobs"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	output, _ := blocked.Callback().ExecuteCommand()
+	assert.Equal(t, []string{"jobs", "a"}, output)
+}
+
+func TestScanLargeKeyspace(t *testing.T) {
+	db := db.NewDb()
+	for i := 0; i < 200000; i++ {
+		db.SetValue("key:"+strconv.Itoa(i), "x")
+	}
+
+	seen := map[string]int{}
+	cursor := "0"
+	for calls := 0; ; calls++ {
+		command, _ := NewCommand("SCAN", db, []string{"SCAN", cursor, "COUNT", "100"})
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		reply := output.([]any)
+		keys := reply[1].([]string)
+		// each call only reads the few buckets it returns
+		assert.LessOrEqual(t, len(keys), 150)
+		for _, key := range keys {
+			seen[key]++
+		}
+		// shrink the keyspace halfway through, which shrinks the index as well
+		if calls == 1000 {
+			for i := 100000; i < 200000; i++ {
+				db.DelValue("key:" + strconv.Itoa(i))
+			}
+		}
+
+		cursor = reply[0].(string)
+		if cursor == "0" {
+			break
+		}
+	}
+
+	for i := 0; i < 100000; i++ {
+		assert.Equal(t, 1, seen["key:"+strconv.Itoa(i)], i)
+	}
+}
+
+func TestScanHugeCount(t *testing.T) {
+	db := db.NewDb()
+	for i := 0; i < 10; i++ {
+		db.SetValue("key:"+strconv.Itoa(i), "x")
+	}
+
+	// COUNT times the visit budget must not overflow into a negative bound
+	command, _ := NewCommand("SCAN", db, []string{"SCAN", "0", "COUNT", "9223372036854775807"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	reply := output.([]any)
+	assert.Equal(t, "0", reply[0])
+	assert.Len(t, reply[1], 10)
+}
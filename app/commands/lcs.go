@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lcsCellSize is the size in bytes of an LCS table cell.
+const lcsCellSize = 4
+
+type LCSCommand struct {
+	baseCommand
+}
+
+func (c *LCSCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'LCS' command")
+	}
+
+	var getLen, getIdx, withMatchLen bool
+	minMatchLen := 0
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "LEN":
+			getLen = true
+		case "IDX":
+			getIdx = true
+		case "WITHMATCHLEN":
+			withMatchLen = true
+		case "MINMATCHLEN":
+			if i+1 >= len(args) {
+				return "", ErrSyntax
+			}
+			number, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return "", ErrNotInteger
+			}
+			minMatchLen = max(number, 0)
+			i++
+		default:
+			return "", ErrSyntax
+		}
+	}
+	if getLen && getIdx {
+		return "", fmt.Errorf("If you want both the length and indexes, please just use IDX.")
+	}
+
+	a, _, errA := getString(c.db, args[1])
+	b, _, errB := getString(c.db, args[2])
+	if errA != nil || errB != nil {
+		return "", fmt.Errorf("The specified keys must contain string values")
+	}
+
+	// like Redis, the table may not take more memory than proto-max-bulk-len;
+	// both strings are bounded by it too, so the size cannot overflow
+	width := len(b) + 1
+	cells := (len(a) + 1) * width
+	if cells*lcsCellSize > maxStringLength {
+		return "", fmt.Errorf("Insufficient memory, transient memory for LCS exceeds proto-max-bulk-len")
+	}
+	// lengths[i*width+j] holds the LCS length of a[:i] and b[:j]
+	lengths := make([]uint32, cells)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				lengths[i*width+j] = lengths[(i-1)*width+j-1] + 1
+			} else {
+				lengths[i*width+j] = max(lengths[(i-1)*width+j], lengths[i*width+j-1])
+			}
+		}
+	}
+	total := int(lengths[cells-1])
+
+	if getLen {
+		return total, nil
+	}
+
+	// walk the table backwards, rebuilding the subsequence and the matching
+	// ranges from the end of both strings like Redis does
+	result := make([]byte, total)
+	matches := []any{}
+	idx := total
+	i, j := len(a), len(b)
+	aStart, aEnd, bStart, bEnd := len(a), 0, 0, 0
+	for i > 0 && j > 0 {
+		emitRange := false
+		if a[i-1] == b[j-1] {
+			result[idx-1] = a[i-1]
+			if aStart == len(a) {
+				aStart, aEnd = i-1, i-1
+				bStart, bEnd = j-1, j-1
+			} else if aStart == i && bStart == j {
+				aStart--
+				bStart--
+			} else {
+				emitRange = true
+			}
+			if aStart == 0 || bStart == 0 {
+				emitRange = true
+			}
+			idx--
+			i--
+			j--
+		} else {
+			if lengths[(i-1)*width+j] > lengths[i*width+j-1] {
+				i--
+			} else {
+				j--
+			}
+			if aStart != len(a) {
+				emitRange = true
+			}
+		}
+
+		if emitRange {
+			matchLen := aEnd - aStart + 1
+			if minMatchLen == 0 || matchLen >= minMatchLen {
+				match := []any{[]any{aStart, aEnd}, []any{bStart, bEnd}}
+				if withMatchLen {
+					match = append(match, matchLen)
+				}
+				matches = append(matches, match)
+			}
+			aStart = len(a)
+		}
+	}
+
+	if getIdx {
+		return []any{"matches", matches, "len", total}, nil
+	}
+	return string(result), nil
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLCSCommand(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("key1", "ohmytext")
+	db.SetValue("key2", "mynewtext")
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"LCS", "key1", "key2"},
+			expectedOutput: "mytext",
+		},
+		{
+			args:           []string{"LCS", "key1", "key2", "LEN"},
+			expectedOutput: 6,
+		},
+		{
+			args: []string{"LCS", "key1", "key2", "IDX"},
+			expectedOutput: []any{
+				"matches",
+				[]any{
+					[]any{[]any{4, 7}, []any{5, 8}},
+					[]any{[]any{2, 3}, []any{0, 1}},
+				},
+				"len", 6,
+			},
+		},
+		{
+			args: []string{"LCS", "key1", "key2", "IDX", "MINMATCHLEN", "4", "WITHMATCHLEN"},
+			expectedOutput: []any{
+				"matches",
+				[]any{
+					[]any{[]any{4, 7}, []any{5, 8}, 4},
+				},
+				"len", 6,
+			},
+		},
+		{
+			args:           []string{"LCS", "key1", "missing"},
+			expectedOutput: "",
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	// a 20KB by 20KB table would take 1.6GB
+	db.SetValue("long1", strings.Repeat("a", 20000))
+	db.SetValue("long2", strings.Repeat("b", 20000))
+	command, _ := NewCommand("LCS", db, []string{"LCS", "long1", "long2"})
+	_, err := command.ExecuteCommand()
+	assert.EqualError(t, err, "Insufficient memory, transient memory for LCS exceeds proto-max-bulk-len")
+
+	serialized := SerializeOutput("LCS", []any{"len", []any{[]any{1, 2}}}, false)
+	assert.Equal(t, "*2\r\n$3\r\nlen\r\n*1\r\n*2\r\n:1\r\n:2\r\n", string(serialized))
+}
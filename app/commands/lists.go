@@ -0,0 +1,611 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+)
+
+// getList returns the entry and list stored at key. A missing key gives a nil
+// entry and no error, a key holding another type gives ErrWrongType.
+func getList(store *db.Db, key string) (*db.MapValue, *quicklist.List, error) {
+	entry, ok := store.GetEntry(key)
+	if !ok {
+		return nil, nil, nil
+	}
+	list, ok := entry.Value.(*quicklist.List)
+	if !ok {
+		return nil, nil, ErrWrongType
+	}
+	return entry, list, nil
+}
+
+// deleteIfEmpty removes key once its list has no elements left.
+func deleteIfEmpty(store *db.Db, key string, list *quicklist.List) {
+	if list.Len() == 0 {
+		store.DelValue(key)
+	}
+}
+
+// listIndex turns a possibly negative index into an offset from the head,
+// reporting whether it falls inside a list of the given length.
+func listIndex(index int, length int) (int, bool) {
+	if index < 0 {
+		index += length
+	}
+	return index, index >= 0 && index < length
+}
+
+// popElements removes up to count elements from the head, or the tail with
+// fromTail, of the list at key, in the order they were popped. A missing key
+// gives nil.
+func popElements(store *db.Db, key string, count int, fromTail bool) ([]string, error) {
+	entry, list, err := getList(store, key)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	popped := make([]string, 0, min(count, list.Len()))
+	for range cap(popped) {
+		element, _ := popEnd(list, fromTail)
+		popped = append(popped, element)
+	}
+	deleteIfEmpty(store, key, list)
+	return popped, nil
+}
+
+func popEnd(list *quicklist.List, fromTail bool) (string, bool) {
+	if fromTail {
+		return list.PopBack()
+	}
+	return list.PopFront()
+}
+
+// popList is popElements as LPOP and RPOP reply it: a single popped element
+// is a plain string, several are a list. A missing key is a null bulk string,
+// or a null array when withCount says a count argument was given.
+func popList(store *db.Db, key string, count int, withCount bool, fromTail bool) (any, error) {
+	popped, err := popElements(store, key, count, fromTail)
+	if err != nil {
+		return "", err
+	}
+	if popped == nil && withCount {
+		return nullArray, nil
+	}
+	if popped == nil {
+		return nil, nil
+	}
+	if len(popped) == 1 && count == 1 {
+		return popped[0], nil
+	}
+	return popped, nil
+}
+
+// parsePopCount reads the optional count argument of LPOP and RPOP.
+func parsePopCount(args []string, name string) (int, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return 0, fmt.Errorf("wrong number of arguments for '%s' command", name)
+	}
+	if len(args) == 2 {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(args[2])
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("value is out of range, must be positive")
+	}
+	return count, nil
+}
+
+type RPOPCommand struct {
+	baseCommand
+}
+
+func (c *RPOPCommand) ExecuteCommand() (any, error) {
+	count, err := parsePopCount(c.args, "RPOP")
+	if err != nil {
+		return "", err
+	}
+	return popList(c.db, c.args[1], count, len(c.args) == 3, true)
+}
+
+type LINDEXCommand struct {
+	baseCommand
+}
+
+func (c *LINDEXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'LINDEX' command")
+	}
+	index, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+
+	entry, list, err := getList(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	index, ok := listIndex(index, list.Len())
+	if !ok {
+		return nil, nil
+	}
+	element, _ := list.Index(index)
+	return element, nil
+}
+
+type LSETCommand struct {
+	baseCommand
+}
+
+func (c *LSETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'LSET' command")
+	}
+	index, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+
+	entry, list, err := getList(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return "", fmt.Errorf("no such key")
+	}
+	index, ok := listIndex(index, list.Len())
+	if !ok {
+		return "", fmt.Errorf("index out of range")
+	}
+	list.Set(index, args[3])
+	return "OK", nil
+}
+
+type LINSERTCommand struct {
+	baseCommand
+}
+
+func (c *LINSERTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 5 {
+		return "", fmt.Errorf("wrong number of arguments for 'LINSERT' command")
+	}
+	var after bool
+	switch strings.ToUpper(args[2]) {
+	case "BEFORE":
+	case "AFTER":
+		after = true
+	default:
+		return "", ErrSyntax
+	}
+	pivot, element := args[3], args[4]
+
+	entry, list, err := getList(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return 0, nil
+	}
+	for i, value := range list.All() {
+		if value != pivot {
+			continue
+		}
+		if after {
+			i++
+		}
+		list.Insert(i, element)
+		return list.Len(), nil
+	}
+	return -1, nil
+}
+
+type LREMCommand struct {
+	baseCommand
+}
+
+func (c *LREMCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'LREM' command")
+	}
+	count, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	element := args[3]
+
+	entry, list, err := getList(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return 0, nil
+	}
+
+	// a negative count removes matches starting from the tail
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	elements := list.All()
+	if count < 0 {
+		elements = list.Backward()
+	}
+	remove := make(map[int]bool)
+	for i, value := range elements {
+		if limit != 0 && len(remove) == limit {
+			break
+		}
+		if value == element {
+			remove[i] = true
+		}
+	}
+	if len(remove) == 0 {
+		return 0, nil
+	}
+
+	kept := quicklist.New()
+	for i, value := range list.All() {
+		if !remove[i] {
+			kept.PushBack(value)
+		}
+	}
+	entry.Value = kept
+	deleteIfEmpty(c.db, args[1], kept)
+	return len(remove), nil
+}
+
+type LTRIMCommand struct {
+	baseCommand
+}
+
+func (c *LTRIMCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'LTRIM' command")
+	}
+	start, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	stop, err := strconv.Atoi(args[3])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+
+	entry, list, err := getList(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return "OK", nil
+	}
+
+	// same index handling as LRANGE, an empty range removes the key
+	if start < 0 {
+		start = max(list.Len()+start, 0)
+	}
+	if stop < 0 {
+		stop = list.Len() + stop
+	}
+	stop = min(stop, list.Len()-1)
+	if start > stop || start >= list.Len() {
+		c.db.DelValue(args[1])
+		return "OK", nil
+	}
+	list.Trim(start, stop)
+	return "OK", nil
+}
+
+type LPOSCommand struct {
+	baseCommand
+}
+
+func (c *LPOSCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'LPOS' command")
+	}
+	element := args[2]
+
+	rank, count, maxLen := 1, -1, 0
+	for i := 3; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return "", ErrSyntax
+		}
+		value, err := strconv.Atoi(args[i+1])
+		if err != nil {
+			return "", ErrNotInteger
+		}
+		switch strings.ToUpper(args[i]) {
+		case "RANK":
+			if value == 0 || value == math.MinInt {
+				return "", fmt.Errorf("RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the end of the list")
+			}
+			rank = value
+		case "COUNT":
+			if value < 0 {
+				return "", fmt.Errorf("COUNT can't be negative")
+			}
+			count = value
+		case "MAXLEN":
+			if value < 0 {
+				return "", fmt.Errorf("MAXLEN can't be negative")
+			}
+			maxLen = value
+		default:
+			return "", ErrSyntax
+		}
+	}
+
+	_, list, err := getList(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+
+	// a negative rank scans from the tail, positions still count from the head
+	skip := rank - 1
+	if rank < 0 {
+		skip = -rank - 1
+	}
+	matches := []int{}
+	if list != nil {
+		elements := list.All()
+		if rank < 0 {
+			elements = list.Backward()
+		}
+		compared := 0
+		for i, value := range elements {
+			if maxLen != 0 && compared == maxLen {
+				break
+			}
+			compared++
+			if value != element {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			matches = append(matches, i)
+			if count != 0 && len(matches) == max(count, 1) {
+				break
+			}
+		}
+	}
+
+	if count < 0 {
+		if len(matches) == 0 {
+			return nil, nil
+		}
+		return matches[0], nil
+	}
+	result := make([]any, len(matches))
+	for i, position := range matches {
+		result[i] = position
+	}
+	return result, nil
+}
+
+// parseListSide reads a LEFT or RIGHT argument, reporting whether it is LEFT.
+func parseListSide(arg string) (bool, error) {
+	switch strings.ToUpper(arg) {
+	case "LEFT":
+		return true, nil
+	case "RIGHT":
+		return false, nil
+	default:
+		return false, ErrSyntax
+	}
+}
+
+// parseBlockTimeout reads the timeout in seconds of a blocking list command.
+func parseBlockTimeout(arg string) (float64, error) {
+	timeout, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(timeout) || math.IsInf(timeout, 0) {
+		return 0, fmt.Errorf("timeout is not a float or out of range")
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("timeout is negative")
+	}
+	// the timer would fire right away if the duration overflowed
+	if timeout >= float64(math.MaxInt64)/float64(time.Second) {
+		return 0, fmt.Errorf("timeout is out of range")
+	}
+	return timeout, nil
+}
+
+// moveElement pops an element from one end of source and pushes it onto one
+// end of destination, replying the element or nil when source is missing.
+func moveElement(store *db.Db, source string, destination string, fromLeft bool, toLeft bool) (any, error) {
+	sourceEntry, sourceList, err := getList(store, source)
+	if err != nil {
+		return "", err
+	}
+	if sourceEntry == nil {
+		return nil, nil
+	}
+	// check the destination first so a wrong type does not lose the element
+	destinationEntry, destinationList, err := getList(store, destination)
+	if err != nil {
+		return "", err
+	}
+
+	element, _ := popEnd(sourceList, !fromLeft)
+	if destinationEntry == nil {
+		destinationList = quicklist.New()
+		store.SetEntry(destination, &db.MapValue{Value: destinationList})
+	}
+	if toLeft {
+		destinationList.PushFront(element)
+	} else {
+		destinationList.PushBack(element)
+	}
+	// pushing before this keeps a list rotated onto itself, with its TTL and
+	// access data
+	deleteIfEmpty(store, source, sourceList)
+	signalListReady(store, destination)
+	return element, nil
+}
+
+// LMOVECommand implements LMOVE and RPOPLPUSH, which is LMOVE with the
+// directions fixed to RIGHT LEFT.
+type LMOVECommand struct {
+	baseCommand
+	legacy bool
+}
+
+func (c *LMOVECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	name := strings.ToUpper(c.GetName())
+	fromLeft, toLeft := false, true
+	if c.legacy {
+		if len(args) != 3 {
+			return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+		}
+	} else {
+		if len(args) != 5 {
+			return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+		}
+		var err error
+		if fromLeft, err = parseListSide(args[3]); err != nil {
+			return "", err
+		}
+		if toLeft, err = parseListSide(args[4]); err != nil {
+			return "", err
+		}
+	}
+	return moveElement(c.db, args[1], args[2], fromLeft, toLeft)
+}
+
+// BLMOVECommand implements BLMOVE and BRPOPLPUSH, blocking on the source list
+// the same way BLPOP does.
+type BLMOVECommand struct {
+	baseCommand
+	legacy bool
+}
+
+func (c *BLMOVECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	name := strings.ToUpper(c.GetName())
+	sides := []string{"RIGHT", "LEFT"}
+	var timeoutArg string
+	if c.legacy {
+		if len(args) != 4 {
+			return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+		}
+		timeoutArg = args[3]
+	} else {
+		if len(args) != 6 {
+			return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+		}
+		for _, side := range args[3:5] {
+			if _, err := parseListSide(side); err != nil {
+				return "", err
+			}
+		}
+		sides = args[3:5]
+		timeoutArg = args[5]
+	}
+	timeout, err := parseBlockTimeout(timeoutArg)
+	if err != nil {
+		return "", err
+	}
+
+	fromLeft, _ := parseListSide(sides[0])
+	toLeft, _ := parseListSide(sides[1])
+	move := func(key string) (any, error) {
+		return moveElement(c.db, key, args[2], fromLeft, toLeft)
+	}
+	output, err := move(args[1])
+	if err != nil || output != nil {
+		return output, err
+	}
+	c.blockOn(args[1:2], timeout, nullArray, move)
+	return nil, nil
+}
+
+// LMPOPCommand implements LMPOP and BLMPOP, popping from the first of the
+// keys that holds a list and replying the key with the popped elements.
+type LMPOPCommand struct {
+	baseCommand
+	blocking bool
+}
+
+func (c *LMPOPCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	name := strings.ToUpper(c.GetName())
+	first := 1
+	if c.blocking {
+		first = 2
+	}
+	if len(args) < first+3 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+	}
+	var timeout float64
+	if c.blocking {
+		var err error
+		if timeout, err = parseBlockTimeout(args[1]); err != nil {
+			return "", err
+		}
+	}
+
+	numKeys, err := strconv.Atoi(args[first])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	if numKeys <= 0 {
+		return "", fmt.Errorf("numkeys should be greater than 0")
+	}
+	if numKeys > len(args)-first-2 {
+		return "", ErrSyntax
+	}
+	keys := args[first+1 : first+1+numKeys]
+	rest := args[first+1+numKeys:]
+
+	fromLeft, err := parseListSide(rest[0])
+	if err != nil {
+		return "", err
+	}
+	count := 1
+	switch {
+	case len(rest) == 1:
+	case len(rest) == 3 && strings.ToUpper(rest[1]) == "COUNT":
+		count, err = strconv.Atoi(rest[2])
+		if err != nil || count <= 0 {
+			return "", fmt.Errorf("count should be greater than 0")
+		}
+	default:
+		return "", ErrSyntax
+	}
+
+	pop := func(key string) (any, error) {
+		popped, err := popElements(c.db, key, count, !fromLeft)
+		if err != nil || popped == nil {
+			return nil, err
+		}
+		return []any{key, popped}, nil
+	}
+	for _, key := range keys {
+		output, err := pop(key)
+		if err != nil || output != nil {
+			return output, err
+		}
+	}
+	if !c.blocking {
+		return nullArray, nil
+	}
+	c.blockOn(keys, timeout, nullArray, pop)
+	return nil, nil
+}
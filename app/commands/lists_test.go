@@ -0,0 +1,442 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"RPUSH", "queue", "a", "b", "c", "b", "a"},
+			expectedOutput: 5,
+		},
+		{
+			args:           []string{"LINDEX", "queue", "-2"},
+			expectedOutput: "b",
+		},
+		{
+			args:           []string{"LINDEX", "queue", "5"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"LSET", "queue", "-1", "z"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"LPOS", "queue", "b"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"LPOS", "queue", "b", "RANK", "-1"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"LPOS", "queue", "b", "COUNT", "0"},
+			expectedOutput: []any{1, 3},
+		},
+		{
+			args:           []string{"LPOS", "queue", "b", "COUNT", "0", "MAXLEN", "2"},
+			expectedOutput: []any{1},
+		},
+		{
+			args:           []string{"LPOS", "queue", "x"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"LINSERT", "queue", "AFTER", "c", "d"},
+			expectedOutput: 6,
+		},
+		{
+			args:           []string{"LINSERT", "queue", "BEFORE", "missing", "d"},
+			expectedOutput: -1,
+		},
+		{
+			args:           []string{"LREM", "queue", "-1", "b"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"LRANGE", "queue", "0", "-1"},
+			expectedOutput: []string{"a", "b", "c", "d", "z"},
+		},
+		{
+			args:           []string{"RPOP", "queue"},
+			expectedOutput: "z",
+		},
+		{
+			args:           []string{"RPOP", "queue", "2"},
+			expectedOutput: []string{"d", "c"},
+		},
+		{
+			args:           []string{"LTRIM", "queue", "-1", "-1"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"LRANGE", "queue", "0", "-1"},
+			expectedOutput: []string{"b"},
+		},
+		{
+			args:           []string{"LREM", "queue", "0", "b"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXISTS", "queue"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"RPOP", "queue"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"RPOP", "queue", "2"},
+			expectedOutput: nullArray,
+		},
+		{
+			args:           []string{"LPOP", "queue", "1"},
+			expectedOutput: nullArray,
+		},
+		{
+			args:           []string{"RPUSH", "capped", "1", "2", "3"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"LTRIM", "capped", "2", "1"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"EXISTS", "capped"},
+			expectedOutput: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	for _, args := range [][]string{
+		{"LSET", "queue", "0", "x"},
+		{"LPOS", "capped", "1", "RANK", "0"},
+		{"RPOP", "capped", "-1"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+}
+
+func TestListMoveCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"RPUSH", "jobs", "1", "2", "3"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"RPOPLPUSH", "jobs", "jobs:processing"},
+			expectedOutput: "3",
+		},
+		{
+			args:           []string{"LMOVE", "jobs", "jobs:processing", "LEFT", "RIGHT"},
+			expectedOutput: "1",
+		},
+		{
+			args:           []string{"LRANGE", "jobs:processing", "0", "-1"},
+			expectedOutput: []string{"3", "1"},
+		},
+		{
+			args:           []string{"LMOVE", "jobs", "jobs", "LEFT", "RIGHT"},
+			expectedOutput: "2",
+		},
+		{
+			args:           []string{"LMOVE", "jobs", "jobs:processing", "RIGHT", "LEFT"},
+			expectedOutput: "2",
+		},
+		{
+			args:           []string{"EXISTS", "jobs"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"RPOPLPUSH", "jobs", "jobs:processing"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"BLMOVE", "jobs", "jobs:processing", "LEFT", "LEFT", "0.01"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"RPUSH", "single", "only"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"EXPIRE", "single", "100"},
+			expectedOutput: 1,
+		},
+		{
+			// rotating a one element list onto itself keeps the key and its TTL
+			args:           []string{"LMOVE", "single", "single", "LEFT", "RIGHT"},
+			expectedOutput: "only",
+		},
+		{
+			args:           []string{"TTL", "single"},
+			expectedOutput: int64(100),
+		},
+		{
+			args:           []string{"LRANGE", "single", "0", "-1"},
+			expectedOutput: []string{"only"},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	db.SetValue("name", "value")
+	command, _ := NewCommand("LMOVE", db, []string{"LMOVE", "jobs:processing", "name", "LEFT", "LEFT"})
+	_, err := command.ExecuteCommand()
+	assert.ErrorIs(t, err, ErrWrongType)
+	length, _ := NewCommand("LLEN", db, []string{"LLEN", "jobs:processing"})
+	output, _ := length.ExecuteCommand()
+	assert.Equal(t, 3, output)
+}
+
+// blockClient runs a blocking command that finds nothing to pop, leaving the
+// client queued on its keys.
+func blockClient(t *testing.T, store *db.Db, args ...string) Command {
+	command, err := NewCommand(args[0], store, args)
+	assert.NoError(t, err)
+	assert.True(t, command.IsBlocking())
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Nil(t, output)
+	assert.NotNil(t, command.Callback())
+	return command
+}
+
+func TestBRPOPLPUSHCommand(t *testing.T) {
+	db := db.NewDb()
+	blocked := blockClient(t, db, "BRPOPLPUSH", "jobs", "jobs:processing", "0")
+
+	push, _ := NewCommand("RPUSH", db, []string{"RPUSH", "jobs", "a", "b"})
+	_, err := push.ExecuteCommand()
+	assert.NoError(t, err)
+	blocked.Block()
+
+	output, err := blocked.Callback().ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, "b", output)
+	value, _ := db.GetValue("jobs:processing")
+	assert.Equal(t, []string{"b"}, value.(*quicklist.List).Slice())
+
+	// a timeout replies a null array, as for the other blocking list commands
+	for _, args := range [][]string{
+		{"BRPOPLPUSH", "empty", "jobs:processing", "0.01"},
+		{"BLMOVE", "empty", "jobs:processing", "LEFT", "RIGHT", "0.01"},
+	} {
+		timed := blockClient(t, db, args...)
+		timed.Block()
+		output, _ = timed.Callback().ExecuteCommand()
+		assert.Equal(t, nullArray, output, args)
+	}
+}
+
+func TestBlockingPops(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("second", quicklist.New("x", "y"))
+
+	command, _ := NewCommand("BRPOP", db, []string{"BRPOP", "first", "second", "0"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second", "y"}, output)
+
+	// clients are served in the order they blocked, one element each
+	first := blockClient(t, db, "BLPOP", "a", "b", "0")
+	second := blockClient(t, db, "BLPOP", "b", "0")
+	third := blockClient(t, db, "BRPOP", "b", "0")
+	assert.Equal(t, 3, db.Waiting("b"))
+
+	push, _ := NewCommand("RPUSH", db, []string{"RPUSH", "b", "1", "2"})
+	output, err = push.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, output)
+
+	output, _ = first.Callback().ExecuteCommand()
+	assert.Equal(t, []string{"b", "1"}, output)
+	output, _ = second.Callback().ExecuteCommand()
+	assert.Equal(t, []string{"b", "2"}, output)
+	assert.Equal(t, 0, db.Waiting("a"))
+	assert.Equal(t, 1, db.Waiting("b"))
+
+	// a timed out client is no longer queued and a later push stays in the list
+	output, _ = third.Callback().ExecuteCommand()
+	assert.Equal(t, nullArray, output)
+	assert.Equal(t, 0, db.Waiting("b"))
+	push, _ = NewCommand("LPUSH", db, []string{"LPUSH", "b", "3"})
+	_, err = push.ExecuteCommand()
+	assert.NoError(t, err)
+	value, _ := db.GetValue("b")
+	assert.Equal(t, []string{"3"}, value.(*quicklist.List).Slice())
+
+	timed := blockClient(t, db, "BLPOP", "c", "0.01")
+	timed.Block()
+	output, _ = timed.Callback().ExecuteCommand()
+	assert.Equal(t, nullArray, output)
+
+	for _, args := range [][]string{
+		{"BLPOP", "c", "-1"},
+		{"BLPOP", "c", "nan"},
+		// would overflow the timer and reply at once instead of blocking
+		{"BLPOP", "c", "1e12"},
+		{"BRPOP", "c", "9223372036.854775807"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+	assert.Equal(t, 0, db.Waiting("c"))
+}
+
+func TestLMPOPCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"LMPOP", "2", "a", "b", "LEFT"},
+			expectedOutput: nullArray,
+		},
+		{
+			args:           []string{"RPUSH", "b", "1", "2", "3"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"LMPOP", "2", "a", "b", "LEFT"},
+			expectedOutput: []any{"b", []string{"1"}},
+		},
+		{
+			args:           []string{"LMPOP", "2", "a", "b", "RIGHT", "COUNT", "5"},
+			expectedOutput: []any{"b", []string{"3", "2"}},
+		},
+		{
+			args:           []string{"EXISTS", "b"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"RPUSH", "a", "x"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"BLMPOP", "0", "2", "a", "b", "LEFT", "COUNT", "2"},
+			expectedOutput: []any{"a", []string{"x"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	blocked := blockClient(t, db, "BLMPOP", "0", "2", "a", "b", "RIGHT", "COUNT", "2")
+	push, _ := NewCommand("RPUSH", db, []string{"RPUSH", "b", "1", "2", "3"})
+	_, err := push.ExecuteCommand()
+	assert.NoError(t, err)
+	output, _ := blocked.Callback().ExecuteCommand()
+	assert.Equal(t, []any{"b", []string{"3", "2"}}, output)
+	assert.Equal(t, []byte("*2\r\n$1\r\nb\r\n*2\r\n$1\r\n3\r\n$1\r\n2\r\n"), SerializeOutput("BLMPOP", output, false))
+
+	// an element that happens to be "-1" is not mistaken for a null array
+	push, _ = NewCommand("RPUSH", db, []string{"RPUSH", "n", "-1"})
+	_, err = push.ExecuteCommand()
+	assert.NoError(t, err)
+	pop, _ := NewCommand("LMPOP", db, []string{"LMPOP", "1", "n", "LEFT"})
+	output, err = pop.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("*2\r\n$1\r\nn\r\n*1\r\n$2\r\n-1\r\n"), SerializeOutput("LMPOP", output, false))
+	output, err = pop.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("*-1\r\n"), SerializeOutput("LMPOP", output, false))
+	assert.Equal(t, []byte("*1\r\n$2\r\n-1\r\n"), SerializeOutput("LRANGE", []string{"-1"}, false))
+
+	for _, args := range [][]string{
+		{"LMPOP", "0", "a", "LEFT"},
+		{"LMPOP", "3", "a", "b", "LEFT"},
+		{"LMPOP", "1", "a", "UP"},
+		{"LMPOP", "1", "a", "LEFT", "COUNT", "0"},
+		{"BLMPOP", "-1", "1", "a", "LEFT"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+}
+
+func TestPushxCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"RPUSHX", "jobs", "a"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"LPUSHX", "jobs", "a"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"EXISTS", "jobs"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"RPUSH", "jobs", "b"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"RPUSHX", "jobs", "c", "d"},
+			expectedOutput: 3,
+		},
+		{
+			args:           []string{"LPUSHX", "jobs", "a"},
+			expectedOutput: 4,
+		},
+		{
+			args:           []string{"LRANGE", "jobs", "0", "-1"},
+			expectedOutput: []string{"a", "b", "c", "d"},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	db.SetValue("name", "value")
+	command, _ := NewCommand("LPUSHX", db, []string{"LPUSHX", "name", "a"})
+	_, err := command.ExecuteCommand()
+	assert.ErrorIs(t, err, ErrWrongType)
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+)
+
+// listpackMaxBytes matches the default list-max-listpack-size of -2 (8kb), the
+// size up to which Redis keeps a list in a single listpack.
+const listpackMaxBytes = 8 * 1024
+
+// hashMaxListpackEntries and hashMaxListpackValue match the defaults of
+// hash-max-listpack-entries and hash-max-listpack-value, the limits up to which
+// Redis keeps a hash in a listpack.
+const (
+	hashMaxListpackEntries = 128
+	hashMaxListpackValue   = 64
+)
+
+// encodingOf reports the encoding Redis would use to store val.
+func encodingOf(val any) string {
+	switch v := val.(type) {
+	case string:
+		if _, ok := parseInteger(v); ok {
+			return "int"
+		}
+		if len(v) <= 44 {
+			return "embstr"
+		}
+		return "raw"
+	case *quicklist.List:
+		size := 0
+		for _, elem := range v.All() {
+			size += len(elem)
+		}
+		if size <= listpackMaxBytes {
+			return "listpack"
+		}
+		return "quicklist"
+	case map[string]string:
+		if len(v) > hashMaxListpackEntries {
+			return "hashtable"
+		}
+		for field, value := range v {
+			if len(field) > hashMaxListpackValue || len(value) > hashMaxListpackValue {
+				return "hashtable"
+			}
+		}
+		return "listpack"
+	default:
+		return "unknown"
+	}
+}
+
+type OBJECTCommand struct {
+	baseCommand
+}
+
+func (c *OBJECTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'OBJECT' command")
+	}
+	subcommand := strings.ToUpper(args[1])
+	if subcommand == "HELP" {
+		return []string{
+			"OBJECT <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+			"ENCODING <key>",
+			"    Return the kind of internal representation used in order to store the value",
+			"    associated with a <key>.",
+			"FREQ <key>",
+			"    Return the access frequency index of the <key>. The returned integer is",
+			"    proportional to the logarithm of the recent access frequency of the key.",
+			"IDLETIME <key>",
+			"    Return the idle time of the <key>, that is the approximated number of",
+			"    seconds elapsed since the last access to the key.",
+			"REFCOUNT <key>",
+			"    Return the number of references of the value associated with the specified",
+			"    <key>.",
+		}, nil
+	}
+	if len(args) != 3 {
+		return "", fmt.Errorf("unknown subcommand or wrong number of arguments for '%s'. Try OBJECT HELP.", args[1])
+	}
+
+	// inspecting a key must not count as an access to it
+	entry, ok := c.db.PeekEntry(args[2])
+	if !ok {
+		return nil, nil
+	}
+	now := time.Now()
+	switch subcommand {
+	case "ENCODING":
+		encoding := encodingOf(entry.Value)
+		// small hashes keep their fields' expiries in the listpack as well
+		if encoding == "listpack" && len(entry.FieldExpireAt) > 0 {
+			return "listpackex", nil
+		}
+		return encoding, nil
+	case "FREQ":
+		return int(entry.AccessFrequency(now)), nil
+	case "IDLETIME":
+		return int64(entry.IdleTime(now) / time.Second), nil
+	case "REFCOUNT":
+		// values are never shared between keys
+		return 1, nil
+	default:
+		return "", fmt.Errorf("unknown subcommand or wrong number of arguments for '%s'. Try OBJECT HELP.", args[1])
+	}
+}
+
+type TOUCHCommand struct {
+	baseCommand
+}
+
+func (c *TOUCHCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'TOUCH' command")
+	}
+	touched := 0
+	for _, key := range args[1:] {
+		if _, ok := c.db.GetEntry(key); ok {
+			touched++
+		}
+	}
+	return touched, nil
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectCommand(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("number", "12345")
+	db.SetValue("short", "hello")
+	db.SetValue("padded", "007")
+	db.SetValue("long", strings.Repeat("x", 100))
+	db.SetValue("list", quicklist.New("a", "b"))
+	db.SetValue("cold", "x")
+	db.DbMap["cold"].LastAccess = time.Now().Add(-90 * time.Second)
+	db.DbMap["cold"].Freq = 6
+
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"OBJECT", "ENCODING", "number"},
+			expectedOutput: "int",
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "short"},
+			expectedOutput: "embstr",
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "padded"},
+			expectedOutput: "embstr",
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "long"},
+			expectedOutput: "raw",
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "list"},
+			expectedOutput: "listpack",
+		},
+		{
+			args:           []string{"OBJECT", "REFCOUNT", "list"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"OBJECT", "ENCODING", "missing"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"OBJECT", "IDLETIME", "cold"},
+			expectedOutput: int64(90),
+		},
+		{
+			// one decay period has passed since the last access
+			args:           []string{"OBJECT", "FREQ", "cold"},
+			expectedOutput: 5,
+		},
+		{
+			args:           []string{"TOUCH", "cold", "missing", "short"},
+			expectedOutput: 2,
+		},
+		{
+			args:           []string{"OBJECT", "IDLETIME", "cold"},
+			expectedOutput: int64(0),
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+}
+
+func TestAccessFrequencyGrows(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("hot", "x")
+	for i := 0; i < 1000; i++ {
+		db.GetValue("hot")
+	}
+	entry, _ := db.PeekEntry("hot")
+	assert.Greater(t, entry.AccessFrequency(time.Now()), uint8(5))
+}
@@ -18,6 +18,10 @@ func SerializeOutput(commandName string, output any, isError bool) []byte {
 		return []byte(fmt.Sprintf(":%d\r\n", v))
 	case []string:
 		return serializeArrayOfStrings(v)
+	case []any:
+		return serializeArray(v)
+	case nullArrayReply:
+		return []byte("*-1\r\n")
 
 	case nil:
 		return []byte("$-1\r\n")
@@ -31,10 +35,14 @@ func serializeString(s string) string {
 	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
 }
 
+// nullArrayReply is the type of nullArray, kept apart from []string so that no
+// actual array can be mistaken for it.
+type nullArrayReply struct{}
+
+// nullArray is the reply for a missing array, such as a BLPOP that timed out.
+var nullArray = nullArrayReply{}
+
 func serializeArrayOfStrings(v []string) []byte {
-	if len(v) == 1 && v[0] == "-1" {
-		return []byte(fmt.Sprintf("*%d\r\n", -1))
-	}
 	var result = fmt.Sprintf("*%d\r\n", len(v))
 	for _, elem := range v {
 		elemSerialized := serializeString(elem)
@@ -43,3 +51,32 @@ func serializeArrayOfStrings(v []string) []byte {
 	return []byte(result)
 
 }
+
+// serializeArray encodes a mixed array of strings, integers and nested arrays
+// where nil elements become null bulk strings.
+func serializeArray(v []any) []byte {
+	var result = fmt.Sprintf("*%d\r\n", len(v))
+	for _, elem := range v {
+		switch e := elem.(type) {
+		case string:
+			result = result + serializeString(e)
+		case int, int64:
+			result = result + fmt.Sprintf(":%d\r\n", e)
+		case []string:
+			result = result + string(serializeArrayOfStrings(e))
+		case []any:
+			nested := serializeArray(e)
+			if nested == nil {
+				return nil
+			}
+			result = result + string(nested)
+		case nullArrayReply:
+			result = result + "*-1\r\n"
+		case nil:
+			result = result + "$-1\r\n"
+		default:
+			return nil
+		}
+	}
+	return []byte(result)
+}
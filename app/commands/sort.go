@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+)
+
+// sortableElements returns the elements SORT works on for a stored value.
+// Further collection types only need a case here.
+func sortableElements(val any) ([]string, error) {
+	switch v := val.(type) {
+	case *quicklist.List:
+		return v.Slice(), nil
+	default:
+		return nil, ErrWrongType
+	}
+}
+
+// lookupByPattern resolves a SORT BY or GET pattern for one element: "#" is
+// the element itself, otherwise the first '*' is replaced by the element and
+// the resulting key is read. A "->field" suffix reads that field of a hash.
+func lookupByPattern(store *db.Db, pattern string, element string) (string, bool) {
+	if pattern == "#" {
+		return element, true
+	}
+	star := strings.IndexByte(pattern, '*')
+	if star < 0 {
+		return "", false
+	}
+	keyPattern, field, hasField := strings.Cut(pattern[star+1:], "->")
+	// a trailing "->" names no field and is part of the key
+	if !hasField || field == "" {
+		keyPattern, hasField = pattern[star+1:], false
+	}
+	key := pattern[:star] + element + keyPattern
+
+	entry, ok := store.GetEntry(key)
+	if !ok {
+		return "", false
+	}
+	if hasField {
+		hash, ok := entry.Value.(map[string]string)
+		if !ok {
+			return "", false
+		}
+		value, ok := hash[field]
+		return value, ok
+	}
+	str, ok := entry.Value.(string)
+	return str, ok
+}
+
+type sortItem struct {
+	element string
+	weight  float64
+	alpha   string
+	hasBy   bool
+}
+
+type SORTCommand struct {
+	baseCommand
+	readOnly bool
+}
+
+func (c *SORTCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	name := strings.ToUpper(c.GetName())
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for '%s' command", name)
+	}
+	key := args[1]
+
+	var byPattern, storeKey string
+	var getPatterns []string
+	var desc, alpha, hasLimit, hasStore bool
+	offset, count := 0, -1
+	for i := 2; i < len(args); i++ {
+		remaining := len(args) - i - 1
+		switch strings.ToUpper(args[i]) {
+		case "ASC":
+			desc = false
+		case "DESC":
+			desc = true
+		case "ALPHA":
+			alpha = true
+		case "LIMIT":
+			if remaining < 2 {
+				return "", ErrSyntax
+			}
+			var err error
+			if offset, err = strconv.Atoi(args[i+1]); err != nil {
+				return "", ErrNotInteger
+			}
+			if count, err = strconv.Atoi(args[i+2]); err != nil {
+				return "", ErrNotInteger
+			}
+			hasLimit = true
+			i += 2
+		case "BY":
+			if remaining < 1 {
+				return "", ErrSyntax
+			}
+			byPattern = args[i+1]
+			i++
+		case "GET":
+			if remaining < 1 {
+				return "", ErrSyntax
+			}
+			getPatterns = append(getPatterns, args[i+1])
+			i++
+		case "STORE":
+			if remaining < 1 || c.readOnly {
+				return "", ErrSyntax
+			}
+			storeKey = args[i+1]
+			hasStore = true
+			i++
+		default:
+			return "", ErrSyntax
+		}
+	}
+
+	var elements []string
+	if entry, ok := c.db.GetEntry(key); ok {
+		var err error
+		if elements, err = sortableElements(entry.Value); err != nil {
+			return "", err
+		}
+	}
+
+	// a BY pattern without '*' means "don't sort", keeping the stored order
+	noSort := byPattern != "" && !strings.Contains(byPattern, "*")
+	if !noSort {
+		items := make([]sortItem, len(elements))
+		for i, element := range elements {
+			items[i].element = element
+			source, found := element, true
+			if byPattern != "" {
+				source, found = lookupByPattern(c.db, byPattern, element)
+			}
+			if alpha {
+				items[i].alpha = source
+				items[i].hasBy = found
+				continue
+			}
+			if !found {
+				continue
+			}
+			weight, err := strconv.ParseFloat(source, 64)
+			if err != nil {
+				return "", fmt.Errorf("One or more scores can't be converted into double")
+			}
+			items[i].weight = weight
+		}
+
+		slices.SortStableFunc(items, func(a, b sortItem) int {
+			var result int
+			if alpha {
+				// missing BY keys sort before every existing value
+				result = cmp.Compare(boolToInt(a.hasBy), boolToInt(b.hasBy))
+				if result == 0 {
+					result = strings.Compare(a.alpha, b.alpha)
+				}
+			} else {
+				result = cmp.Compare(a.weight, b.weight)
+			}
+			// equal weights fall back to comparing the elements themselves
+			if result == 0 {
+				result = strings.Compare(a.element, b.element)
+			}
+			if desc {
+				return -result
+			}
+			return result
+		})
+		for i := range items {
+			elements[i] = items[i].element
+		}
+	}
+
+	if hasLimit {
+		start := min(max(offset, 0), len(elements))
+		end := len(elements)
+		// compared before adding so a huge count cannot overflow
+		if count >= 0 && count < len(elements)-start {
+			end = start + count
+		}
+		elements = elements[start:end]
+	}
+
+	if len(getPatterns) == 0 {
+		if hasStore {
+			return c.store(storeKey, elements), nil
+		}
+		return elements, nil
+	}
+
+	result := make([]any, 0, len(elements)*len(getPatterns))
+	for _, element := range elements {
+		for _, pattern := range getPatterns {
+			value, ok := lookupByPattern(c.db, pattern, element)
+			if !ok {
+				result = append(result, nil)
+				continue
+			}
+			result = append(result, value)
+		}
+	}
+	if hasStore {
+		// STORE keeps missing GET values as empty strings
+		values := make([]string, len(result))
+		for i, value := range result {
+			if str, ok := value.(string); ok {
+				values[i] = str
+			}
+		}
+		return c.store(storeKey, values), nil
+	}
+	return result, nil
+}
+
+// store saves the sorted result as a list at key, deleting key when it is empty.
+func (c *SORTCommand) store(key string, values []string) int {
+	if len(values) == 0 {
+		c.db.DelValue(key)
+		return 0
+	}
+	c.db.SetValue(key, quicklist.New(values...))
+	signalListReady(c.db, key)
+	return len(values)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
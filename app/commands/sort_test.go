@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortCommand(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"RPUSH", "jobs", "3", "1", "10", "2"},
+			expectedOutput: 4,
+		},
+		{
+			args:           []string{"SORT", "jobs"},
+			expectedOutput: []string{"1", "2", "3", "10"},
+		},
+		{
+			args:           []string{"SORT", "jobs", "DESC", "LIMIT", "1", "2"},
+			expectedOutput: []string{"3", "2"},
+		},
+		{
+			args:           []string{"SORT", "jobs", "LIMIT", "1", "9223372036854775807"},
+			expectedOutput: []string{"2", "3", "10"},
+		},
+		{
+			args:           []string{"SORT", "jobs", "ALPHA"},
+			expectedOutput: []string{"1", "10", "2", "3"},
+		},
+		{
+			args:           []string{"MSET", "weight_1", "30", "weight_2", "10", "weight_3", "20", "name_1", "one", "name_3", "three"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"SORT", "jobs", "BY", "weight_*"},
+			expectedOutput: []string{"10", "2", "3", "1"},
+		},
+		{
+			args:           []string{"SORT", "jobs", "BY", "nosort", "GET", "#", "GET", "name_*"},
+			expectedOutput: []any{"3", "three", "1", "one", "10", nil, "2", nil},
+		},
+		{
+			args:           []string{"SORT_RO", "jobs", "BY", "weight_*", "GET", "name_*"},
+			expectedOutput: []any{nil, nil, "three", "one"},
+		},
+		{
+			args:           []string{"SORT", "jobs", "STORE", "sorted"},
+			expectedOutput: 4,
+		},
+		{
+			args:           []string{"LRANGE", "sorted", "0", "-1"},
+			expectedOutput: []string{"1", "2", "3", "10"},
+		},
+		{
+			args:           []string{"SORT", "missing", "STORE", "sorted"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"EXISTS", "sorted"},
+			expectedOutput: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output, tt.args)
+	}
+
+	// a list whose only element is "-1" is still sent as an array
+	db.SetValue("negative", quicklist.New("-1"))
+	for _, args := range [][]string{
+		{"SORT", "negative"},
+		{"SORT_RO", "negative"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("*1\r\n$2\r\n-1\r\n"), SerializeOutput(args[0], output, false), args)
+	}
+
+	for _, args := range [][]string{
+		{"SORT_RO", "jobs", "STORE", "sorted"},
+		{"SORT", "jobs", "BY", "name_*"},
+		{"SORT", "weight_1"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+}
@@ -0,0 +1,515 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+)
+
+// incrementBy adds delta to the integer stored at key. A missing key starts at 0
+// and an existing key keeps its expiry.
+func incrementBy(store *db.Db, key string, delta int64) (any, error) {
+	var current int64
+	entry, ok := store.GetEntry(key)
+	if ok {
+		str, isString := entry.Value.(string)
+		if !isString {
+			return "", ErrWrongType
+		}
+		number, ok := parseInteger(str)
+		if !ok {
+			return "", ErrNotInteger
+		}
+		current = number
+	}
+
+	if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+		return "", ErrOverflow
+	}
+	current += delta
+
+	if ok {
+		entry.Value = strconv.FormatInt(current, 10)
+	} else {
+		store.SetValue(key, strconv.FormatInt(current, 10))
+	}
+	return current, nil
+}
+
+type INCRCommand struct {
+	baseCommand
+}
+
+func (c *INCRCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'INCR' command")
+	}
+	return incrementBy(c.db, args[1], 1)
+}
+
+type DECRCommand struct {
+	baseCommand
+}
+
+func (c *DECRCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'DECR' command")
+	}
+	return incrementBy(c.db, args[1], -1)
+}
+
+type INCRBYCommand struct {
+	baseCommand
+}
+
+func (c *INCRBYCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'INCRBY' command")
+	}
+	increment, ok := parseInteger(args[2])
+	if !ok {
+		return "", ErrNotInteger
+	}
+	return incrementBy(c.db, args[1], increment)
+}
+
+type DECRBYCommand struct {
+	baseCommand
+}
+
+func (c *DECRBYCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'DECRBY' command")
+	}
+	decrement, ok := parseInteger(args[2])
+	if !ok {
+		return "", ErrNotInteger
+	}
+	// -math.MinInt64 does not fit in an int64
+	if decrement == math.MinInt64 {
+		return "", fmt.Errorf("decrement would overflow")
+	}
+	return incrementBy(c.db, args[1], -decrement)
+}
+
+type INCRBYFLOATCommand struct {
+	baseCommand
+}
+
+func (c *INCRBYFLOATCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'INCRBYFLOAT' command")
+	}
+	key := args[1]
+	increment, err := parseLongDouble(args[2])
+	if err != nil {
+		return "", err
+	}
+
+	current := new(big.Float)
+	entry, ok := c.db.GetEntry(key)
+	if ok {
+		str, isString := entry.Value.(string)
+		if !isString {
+			return "", ErrWrongType
+		}
+		current, err = parseLongDouble(str)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	result, err := addLongDouble(current, increment)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		entry.Value = result
+	} else {
+		c.db.SetValue(key, result)
+	}
+	return result, nil
+}
+
+// parseInteger parses a 64 bit integer as strictly as Redis' string2ll does:
+// an optional minus sign and digits without leading zeros, so that "+5",
+// "007" and " 1" are not integers.
+func parseInteger(s string) (int64, bool) {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || (digits[0] == '0' && s != "0") {
+		return 0, false
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, false
+		}
+	}
+	number, err := strconv.ParseInt(s, 10, 64)
+	return number, err == nil
+}
+
+// longDoublePrec is the mantissa size of the x87 long double Redis computes
+// INCRBYFLOAT and HINCRBYFLOAT in.
+const longDoublePrec = 64
+
+// parseLongDouble is parseFloat at long double precision.
+func parseLongDouble(s string) (*big.Float, error) {
+	if _, err := parseFloat(s); err != nil {
+		return nil, err
+	}
+	number, _, err := big.ParseFloat(s, 10, longDoublePrec, big.ToNearestEven)
+	if err != nil {
+		return nil, ErrNotFloat
+	}
+	return number, nil
+}
+
+// addLongDouble adds two long doubles and formats the sum like Redis' ld2string
+// in human mode: 17 decimals with the trailing zeros trimmed, so that
+// 0.1 + 0.2 gives "0.3".
+func addLongDouble(a *big.Float, b *big.Float) (string, error) {
+	sum := new(big.Float).SetPrec(longDoublePrec).Add(a, b)
+	// keep stored values readable by parseFloat
+	if f, _ := sum.Float64(); math.IsInf(f, 0) {
+		return "", fmt.Errorf("increment would produce NaN or Infinity")
+	}
+	result := sum.Text('f', 17)
+	result = strings.TrimRight(result, "0")
+	result = strings.TrimSuffix(result, ".")
+	if result == "-0" {
+		result = "0"
+	}
+	return result, nil
+}
+
+// parseFloat parses a finite float the way Redis does, rejecting NaN and infinities.
+func parseFloat(s string) (float64, error) {
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, ErrNotFloat
+	}
+	return number, nil
+}
+
+// getString returns the string stored at key, or ErrWrongType when key holds another type.
+func getString(store *db.Db, key string) (string, bool, error) {
+	entry, ok := store.GetEntry(key)
+	if !ok {
+		return "", false, nil
+	}
+	str, isString := entry.Value.(string)
+	if !isString {
+		return "", true, ErrWrongType
+	}
+	return str, true, nil
+}
+
+// setStringKeepTTL stores value at key, leaving any expiry already set on the key untouched.
+func setStringKeepTTL(store *db.Db, key string, value string) {
+	entry, ok := store.GetEntry(key)
+	if !ok {
+		store.SetValue(key, value)
+		return
+	}
+	entry.Value = value
+}
+
+type APPENDCommand struct {
+	baseCommand
+}
+
+func (c *APPENDCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'APPEND' command")
+	}
+	key := args[1]
+
+	current, _, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	if len(args[2]) > maxStringLength-len(current) {
+		return "", fmt.Errorf("string exceeds maximum allowed size (proto-max-bulk-len)")
+	}
+	newValue := current + args[2]
+	setStringKeepTTL(c.db, key, newValue)
+	return len(newValue), nil
+}
+
+type STRLENCommand struct {
+	baseCommand
+}
+
+func (c *STRLENCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'STRLEN' command")
+	}
+	current, _, err := getString(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+	return len(current), nil
+}
+
+type GETRANGECommand struct {
+	baseCommand
+}
+
+func (c *GETRANGECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'GETRANGE' command")
+	}
+	start, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	end, err := strconv.Atoi(args[3])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	current, _, err := getString(c.db, args[1])
+	if err != nil {
+		return "", err
+	}
+
+	length := len(current)
+	if start < 0 && end < 0 && start > end {
+		return "", nil
+	}
+	if start < 0 {
+		start = length + start
+	}
+	if end < 0 {
+		end = length + end
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end = 0
+	}
+	if end >= length {
+		end = length - 1
+	}
+	if length == 0 || start > end {
+		return "", nil
+	}
+	return current[start : end+1], nil
+}
+
+// maxStringLength mirrors Redis' proto-max-bulk-len default of 512MB.
+const maxStringLength = 512 * 1024 * 1024
+
+type SETRANGECommand struct {
+	baseCommand
+}
+
+func (c *SETRANGECommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 4 {
+		return "", fmt.Errorf("wrong number of arguments for 'SETRANGE' command")
+	}
+	key := args[1]
+	offset, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", ErrNotInteger
+	}
+	if offset < 0 {
+		return "", fmt.Errorf("offset is out of range")
+	}
+	value := args[3]
+
+	current, _, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	if len(value) == 0 {
+		return len(current), nil
+	}
+	if offset > maxStringLength-len(value) {
+		return "", fmt.Errorf("string exceeds maximum allowed size (proto-max-bulk-len)")
+	}
+
+	buf := []byte(current)
+	if end := offset + len(value); end > len(buf) {
+		// the gap between the old end of the string and offset is zero padded
+		buf = append(buf, make([]byte, end-len(buf))...)
+	}
+	copy(buf[offset:], value)
+
+	setStringKeepTTL(c.db, key, string(buf))
+	return len(buf), nil
+}
+
+type MGETCommand struct {
+	baseCommand
+}
+
+func (c *MGETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'MGET' command")
+	}
+
+	// keys that are missing or hold a non-string value come back as nil holes
+	result := make([]any, 0, len(args)-1)
+	for _, key := range args[1:] {
+		str, ok, err := getString(c.db, key)
+		if !ok || err != nil {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
+
+type MSETCommand struct {
+	baseCommand
+}
+
+func (c *MSETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 || len(args)%2 == 0 {
+		return "", fmt.Errorf("wrong number of arguments for 'MSET' command")
+	}
+	for i := 1; i < len(args); i += 2 {
+		c.db.SetValue(args[i], args[i+1])
+	}
+	return "OK", nil
+}
+
+type MSETNXCommand struct {
+	baseCommand
+}
+
+func (c *MSETNXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 3 || len(args)%2 == 0 {
+		return "", fmt.Errorf("wrong number of arguments for 'MSETNX' command")
+	}
+	for i := 1; i < len(args); i += 2 {
+		if _, ok := c.db.GetEntry(args[i]); ok {
+			return 0, nil
+		}
+	}
+	for i := 1; i < len(args); i += 2 {
+		c.db.SetValue(args[i], args[i+1])
+	}
+	return 1, nil
+}
+
+type GETDELCommand struct {
+	baseCommand
+}
+
+func (c *GETDELCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'GETDEL' command")
+	}
+	key := args[1]
+
+	str, ok, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return nil, nil
+	}
+	c.db.DelValue(key)
+	return str, nil
+}
+
+type GETSETCommand struct {
+	baseCommand
+}
+
+func (c *GETSETCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) != 3 {
+		return "", fmt.Errorf("wrong number of arguments for 'GETSET' command")
+	}
+	key := args[1]
+
+	str, ok, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	// like SET, GETSET discards any existing expiry
+	c.db.SetValue(key, args[2])
+	if !ok {
+		return nil, nil
+	}
+	return str, nil
+}
+
+type GETEXCommand struct {
+	baseCommand
+}
+
+func (c *GETEXCommand) ExecuteCommand() (any, error) {
+	args := c.args
+	if len(args) < 2 {
+		return "", fmt.Errorf("wrong number of arguments for 'GETEX' command")
+	}
+	key := args[1]
+
+	var persist bool
+	var expiryOption string
+	var expireAt time.Time
+	for i := 2; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		switch option {
+		case "PERSIST":
+			if expiryOption != "" {
+				return "", ErrSyntax
+			}
+			persist = true
+		case "EX", "PX", "EXAT", "PXAT":
+			if expiryOption != "" || persist || i+1 >= len(args) {
+				return "", ErrSyntax
+			}
+			var err error
+			expireAt, err = parseExpiry(option, args[i+1], "getex")
+			if err != nil {
+				return "", err
+			}
+			expiryOption = option
+			i++
+		default:
+			return "", ErrSyntax
+		}
+	}
+
+	str, ok, err := getString(c.db, key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	entry := c.db.DbMap[key]
+	if persist {
+		entry.HasExpiryDate = false
+		entry.ExpireAt = time.Time{}
+	} else if expiryOption != "" {
+		entry.HasExpiryDate = true
+		entry.ExpireAt = expireAt
+	}
+	return str, nil
+}
@@ -0,0 +1,293 @@
+package commands
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/db"
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+		expectedError  error
+	}{
+		{
+			args:           []string{"INCR", "counter"},
+			expectedOutput: int64(1),
+		},
+		{
+			args:           []string{"INCRBY", "counter", "41"},
+			expectedOutput: int64(42),
+		},
+		{
+			args:           []string{"DECR", "counter"},
+			expectedOutput: int64(41),
+		},
+		{
+			args:           []string{"DECRBY", "counter", "50"},
+			expectedOutput: int64(-9),
+		},
+		{
+			args:           []string{"INCRBY", "counter", "abc"},
+			expectedOutput: "",
+			expectedError:  ErrNotInteger,
+		},
+		{
+			args:           []string{"INCRBY", "counter", "+5"},
+			expectedOutput: "",
+			expectedError:  ErrNotInteger,
+		},
+		{
+			args:           []string{"SET", "padded", "007"},
+			expectedOutput: "OK",
+		},
+		{
+			// like Redis, leading zeros do not make an integer
+			args:           []string{"INCR", "padded"},
+			expectedOutput: "",
+			expectedError:  ErrNotInteger,
+		},
+		{
+			args:           []string{"INCRBYFLOAT", "counter", "0.5"},
+			expectedOutput: "-8.5",
+		},
+		{
+			args:           []string{"INCRBYFLOAT", "tenths", "0.1"},
+			expectedOutput: "0.1",
+		},
+		{
+			// long double arithmetic leaves no float64 rounding noise
+			args:           []string{"INCRBYFLOAT", "tenths", "0.2"},
+			expectedOutput: "0.3",
+		},
+		{
+			args:           []string{"INCRBYFLOAT", "tenths", "-0.3"},
+			expectedOutput: "0",
+		},
+		{
+			args:           []string{"SET", "exponent", "5.0e3"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"INCRBYFLOAT", "exponent", "2.0e2"},
+			expectedOutput: "5200",
+		},
+		{
+			args:           []string{"INCR", "counter"},
+			expectedOutput: "",
+			expectedError:  ErrNotInteger,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.Equal(t, tt.expectedError, err)
+		assert.Equal(t, tt.expectedOutput, output)
+	}
+}
+
+func TestIncrOverflow(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("counter", strconv.FormatInt(math.MaxInt64, 10))
+	db.SetValue("list", quicklist.New("a"))
+
+	command, _ := NewCommand("INCR", db, []string{"INCR", "counter"})
+	_, err := command.ExecuteCommand()
+	assert.Equal(t, ErrOverflow, err)
+
+	command, _ = NewCommand("INCR", db, []string{"INCR", "list"})
+	_, err = command.ExecuteCommand()
+	assert.Equal(t, ErrWrongType, err)
+
+	val, _ := db.GetValue("counter")
+	assert.Equal(t, strconv.FormatInt(math.MaxInt64, 10), val)
+}
+
+func TestStringRangeCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"APPEND", "greeting", "Hello"},
+			expectedOutput: 5,
+		},
+		{
+			args:           []string{"APPEND", "greeting", " World"},
+			expectedOutput: 11,
+		},
+		{
+			args:           []string{"STRLEN", "greeting"},
+			expectedOutput: 11,
+		},
+		{
+			args:           []string{"GETRANGE", "greeting", "0", "4"},
+			expectedOutput: "Hello",
+		},
+		{
+			args:           []string{"GETRANGE", "greeting", "-5", "-1"},
+			expectedOutput: "World",
+		},
+		{
+			args:           []string{"GETRANGE", "greeting", "5", "1"},
+			expectedOutput: "",
+		},
+		{
+			args:           []string{"SETRANGE", "greeting", "6", "Redis"},
+			expectedOutput: 11,
+		},
+		{
+			args:           []string{"GET", "greeting"},
+			expectedOutput: "Hello Redis",
+		},
+		{
+			args:           []string{"SETRANGE", "padded", "3", "abc"},
+			expectedOutput: 6,
+		},
+		{
+			args:           []string{"GET", "padded"},
+			expectedOutput: "\x00\x00\x00abc",
+		},
+		{
+			args:           []string{"STRLEN", "missing"},
+			expectedOutput: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output)
+	}
+
+	for _, args := range [][]string{
+		{"SETRANGE", "greeting", "-1", "abc"},
+		{"SETRANGE", "greeting", "536870910", "abc"},
+		{"SETRANGE", "greeting", "9223372036854775807", "abc"},
+	} {
+		command, _ := NewCommand(args[0], db, args)
+		_, err := command.ExecuteCommand()
+		assert.Error(t, err, args)
+	}
+
+	// "Hello Redis" plus this is one byte over the limit
+	command, _ := NewCommand("APPEND", db, []string{"APPEND", "greeting", strings.Repeat("x", maxStringLength-10)})
+	_, err := command.ExecuteCommand()
+	assert.EqualError(t, err, "string exceeds maximum allowed size (proto-max-bulk-len)")
+}
+
+func TestAppendKeepsTTL(t *testing.T) {
+	db := db.NewDb()
+	command, _ := NewCommand("SET", db, []string{"SET", "foo", "bar", "PX", "10000"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+
+	command, _ = NewCommand("APPEND", db, []string{"APPEND", "foo", "baz"})
+	_, err = command.ExecuteCommand()
+	assert.NoError(t, err)
+
+	entry, ok := db.GetEntry("foo")
+	assert.True(t, ok)
+	assert.Equal(t, "barbaz", entry.Value)
+	assert.True(t, entry.HasExpiryDate)
+}
+
+func TestMultiKeyCommands(t *testing.T) {
+	db := db.NewDb()
+	db.SetValue("list", quicklist.New("a"))
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"MSET", "a", "1", "b", "2"},
+			expectedOutput: "OK",
+		},
+		{
+			args:           []string{"MGET", "a", "missing", "b", "list"},
+			expectedOutput: []any{"1", nil, "2", nil},
+		},
+		{
+			args:           []string{"MSETNX", "c", "3", "a", "10"},
+			expectedOutput: 0,
+		},
+		{
+			args:           []string{"MSETNX", "c", "3", "d", "4"},
+			expectedOutput: 1,
+		},
+		{
+			args:           []string{"MGET", "a", "c", "d"},
+			expectedOutput: []any{"1", "3", "4"},
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output)
+	}
+
+	serialized := SerializeOutput("MGET", []any{"1", nil}, false)
+	assert.Equal(t, "*2\r\n$1\r\n1\r\n$-1\r\n", string(serialized))
+}
+
+func TestGetAndModifyCommands(t *testing.T) {
+	db := db.NewDb()
+	testCases := []struct {
+		args           []string
+		expectedOutput any
+	}{
+		{
+			args:           []string{"GETSET", "session", "v1"},
+			expectedOutput: nil,
+		},
+		{
+			args:           []string{"GETSET", "session", "v2"},
+			expectedOutput: "v1",
+		},
+		{
+			args:           []string{"GETEX", "session", "PX", "10000"},
+			expectedOutput: "v2",
+		},
+	}
+
+	for _, tt := range testCases {
+		command, err := NewCommand(tt.args[0], db, tt.args)
+		assert.NoError(t, err)
+		output, err := command.ExecuteCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedOutput, output)
+	}
+	entry, _ := db.GetEntry("session")
+	assert.True(t, entry.HasExpiryDate)
+
+	command, _ := NewCommand("GETEX", db, []string{"GETEX", "session", "PERSIST"})
+	_, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.False(t, entry.HasExpiryDate)
+
+	command, _ = NewCommand("GETEX", db, []string{"GETEX", "session", "PERSIST", "EX", "10"})
+	_, err = command.ExecuteCommand()
+	assert.Equal(t, ErrSyntax, err)
+
+	command, _ = NewCommand("GETDEL", db, []string{"GETDEL", "session"})
+	output, err := command.ExecuteCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", output)
+	_, ok := db.GetValue("session")
+	assert.False(t, ok)
+}
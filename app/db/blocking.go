@@ -0,0 +1,95 @@
+package db
+
+import "slices"
+
+// Waiter is a client blocked until one of its keys can serve it, as BLPOP
+// does. Waiters are queued per key in arrival order, and everything touching
+// them runs on the event loop.
+type Waiter struct {
+	Keys []string
+	// serve is tried with a key that may be ready and reports whether it
+	// consumed from that key
+	serve  func(key string) bool
+	served bool
+	// Ready is closed once the waiter has been served
+	Ready chan struct{}
+}
+
+func NewWaiter(keys []string, serve func(key string) bool) *Waiter {
+	return &Waiter{
+		Keys:  keys,
+		serve: serve,
+		Ready: make(chan struct{}),
+	}
+}
+
+// Served reports whether the waiter has been served, after which it is no
+// longer queued on any key.
+func (w *Waiter) Served() bool {
+	return w.served
+}
+
+// Block queues w on each of its keys behind the clients already waiting there.
+func (db *Db) Block(w *Waiter) {
+	for _, key := range w.Keys {
+		if slices.Contains(db.waiters[key], w) {
+			continue
+		}
+		db.waiters[key] = append(db.waiters[key], w)
+	}
+}
+
+// Unblock removes w from every key it waits on.
+func (db *Db) Unblock(w *Waiter) {
+	for _, key := range w.Keys {
+		queue := slices.DeleteFunc(db.waiters[key], func(other *Waiter) bool {
+			return other == w
+		})
+		if len(queue) == 0 {
+			delete(db.waiters, key)
+			continue
+		}
+		db.waiters[key] = queue
+	}
+}
+
+// ServeWaiters hands key to the clients waiting on it, first come first
+// served, until one of them finds nothing left to consume. Keys that become
+// ready while a waiter is being served, as with BLMOVE pushing onto a list,
+// are queued and handled after it.
+func (db *Db) ServeWaiters(key string) {
+	db.readyKeys = append(db.readyKeys, key)
+	if db.serving {
+		return
+	}
+	db.serving = true
+	defer func() { db.serving = false }()
+
+	for len(db.readyKeys) > 0 {
+		key := db.readyKeys[0]
+		db.readyKeys = db.readyKeys[1:]
+		for len(db.waiters[key]) > 0 {
+			w := db.waiters[key][0]
+			if !w.serve(key) {
+				break
+			}
+			w.served = true
+			db.Unblock(w)
+			close(w.Ready)
+		}
+	}
+}
+
+// WaitingKeys returns every key some client is blocked on.
+func (db *Db) WaitingKeys() []string {
+	keys := make([]string, 0, len(db.waiters))
+	for key := range db.waiters {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Waiting returns how many clients are blocked on key.
+func (db *Db) Waiting(key string) int {
+	return len(db.waiters[key])
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"time"
+)
+
+// Databases holds the numbered logical databases clients SELECT between.
+type Databases struct {
+	Dbs []*Db
+}
+
+func NewDatabases(count int) *Databases {
+	databases := &Databases{Dbs: make([]*Db, count)}
+	for i := range databases.Dbs {
+		databases.Dbs[i] = NewDb()
+		databases.Dbs[i].Index = i
+		databases.Dbs[i].databases = databases
+	}
+	return databases
+}
+
+// Get returns the database with the given index.
+func (d *Databases) Get(index int) (*Db, bool) {
+	if index < 0 || index >= len(d.Dbs) {
+		return nil, false
+	}
+	return d.Dbs[index], true
+}
+
+// ActiveExpireCycle runs the active expire cycle over every database, sharing
+// timeLimit between them.
+func (d *Databases) ActiveExpireCycle(timeLimit time.Duration) int {
+	start := time.Now()
+	expired := 0
+	for _, db := range d.Dbs {
+		remaining := timeLimit - time.Since(start)
+		if remaining <= 0 {
+			break
+		}
+		expired += db.ActiveExpireCycle(remaining)
+	}
+	return expired
+}
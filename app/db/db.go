@@ -9,37 +9,138 @@ type MapValue struct {
 	SetAt         time.Time
 	HasExpiryDate bool
 	ExpireAt      time.Time
+	// LastAccess and Freq back OBJECT IDLETIME and OBJECT FREQ, see object.go
+	LastAccess time.Time
+	Freq       uint8
+	// FieldExpireAt holds the expiry times of a hash's fields, which are set
+	// through SetFieldExpiry, see fields.go
+	FieldExpireAt   map[string]time.Time
+	nextFieldExpiry time.Time
 }
 
 type Db struct {
-	DbMap        map[any]*MapValue
-	ListChannels map[string]chan bool
+	// DbMap is only changed through Db's methods, which keep scan in sync
+	DbMap map[any]*MapValue
+	scan  *scanIndex
+	// Index is the number clients SELECT this database by
+	Index     int
+	databases *Databases
+	// waiters holds the clients blocked on each key, see blocking.go
+	waiters   map[string][]*Waiter
+	readyKeys []string
+	serving   bool
 }
 
 func NewDb() *Db {
 	return &Db{
-		DbMap:        make(map[any]*MapValue),
-		ListChannels: make(map[string]chan bool),
+		DbMap:   make(map[any]*MapValue),
+		scan:    newScanIndex(),
+		waiters: make(map[string][]*Waiter),
 	}
 }
 
-func (db *Db) GetValue(key string) (any, bool) {
+// Sibling returns the database numbered index on the same server. A Db
+// created on its own with NewDb only knows itself, as database 0.
+func (db *Db) Sibling(index int) (*Db, bool) {
+	if db.databases == nil {
+		if index == db.Index {
+			return db, true
+		}
+		return nil, false
+	}
+	return db.databases.Get(index)
+}
+
+// Siblings returns every database on the same server, including db itself.
+func (db *Db) Siblings() []*Db {
+	if db.databases == nil {
+		return []*Db{db}
+	}
+	return db.databases.Dbs
+}
+
+// GetEntry returns the stored entry for key, deleting it first if it has expired,
+// and records the access.
+func (db *Db) GetEntry(key string) (*MapValue, bool) {
+	val, ok := db.PeekEntry(key)
+	if !ok {
+		return nil, false
+	}
+	val.Touch(time.Now())
+	return val, true
+}
+
+// PeekEntry is GetEntry without recording an access, for commands such as TTL
+// or OBJECT that inspect a key without using its value.
+func (db *Db) PeekEntry(key string) (*MapValue, bool) {
 	val, ok := db.DbMap[key]
 	if !ok {
 		return nil, false
 	}
 
-	if val.HasExpiryDate && time.Now().After(val.ExpireAt) {
-		delete(db.DbMap, key)
+	now := time.Now()
+	if val.HasExpiryDate && now.After(val.ExpireAt) {
+		db.DelValue(key)
+		return nil, false
+	}
+	if db.expireFields(key, val, now) > 0 {
+		if _, ok := db.DbMap[key]; !ok {
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+func (db *Db) GetValue(key string) (any, bool) {
+	val, ok := db.GetEntry(key)
+	if !ok {
 		return nil, false
 	}
 	return val.Value, true
 }
 
 func (db *Db) SetValue(key string, value any) {
-	db.DbMap[key] = &MapValue{Value: value}
+	db.SetEntry(key, &MapValue{Value: value})
 }
+
+// SetEntry stores val at key as a freshly created value.
+func (db *Db) SetEntry(key string, val *MapValue) {
+	now := time.Now()
+	if val.SetAt.IsZero() {
+		val.SetAt = now
+	}
+	val.LastAccess = now
+	val.Freq = lfuInitVal
+	db.PutEntry(key, val)
+}
+
+// PutEntry stores val at key as it is, for commands that move an existing
+// value to another key or database along with its metadata.
+func (db *Db) PutEntry(key string, val *MapValue) {
+	db.DbMap[key] = val
+	db.scan.add(key)
+}
+
 func (db *Db) DelValue(key string) {
 	delete(db.DbMap, key)
-	delete(db.ListChannels, key)
+	db.scan.remove(key)
+}
+
+// SwapData exchanges every key of db with those of other.
+func (db *Db) SwapData(other *Db) {
+	db.DbMap, other.DbMap = other.DbMap, db.DbMap
+	db.scan, other.scan = other.scan, db.scan
+}
+
+// Flush removes every key. With async the old keys are released by a
+// background goroutine so a large database does not stall the event loop.
+func (db *Db) Flush(async bool) {
+	old := db.DbMap
+	db.DbMap = make(map[any]*MapValue)
+	db.scan = newScanIndex()
+	if async {
+		go clear(old)
+	} else {
+		clear(old)
+	}
 }
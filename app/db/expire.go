@@ -0,0 +1,57 @@
+package db
+
+import (
+	"time"
+)
+
+const (
+	// activeExpireKeysPerLoop is how many keys with a TTL are sampled per round.
+	activeExpireKeysPerLoop = 20
+	// activeExpireMaxChecked bounds how many keys are walked to find those samples,
+	// so a keyspace with few volatile keys does not get scanned in full.
+	activeExpireMaxChecked = activeExpireKeysPerLoop * 20
+	// activeExpireAcceptableStale is the percentage of expired keys in a sample
+	// below which the cycle stops early.
+	activeExpireAcceptableStale = 10
+)
+
+// ActiveExpireCycle deletes expired keys that nobody has read, mirroring Redis'
+// adaptive sampling: it samples keys with a TTL, deletes the expired ones and
+// samples again while more than activeExpireAcceptableStale percent of a sample
+// had expired, giving up once timeLimit has been spent. Expired hash fields are
+// deleted along the way. It returns the number of keys it deleted or expired
+// fields of.
+func (db *Db) ActiveExpireCycle(timeLimit time.Duration) int {
+	start := time.Now()
+	expired := 0
+	for {
+		sampled, expiredNow, checked := 0, 0, 0
+		now := time.Now()
+		// map iteration starts at a random position, which gives us the sample
+		for mapKey, val := range db.DbMap {
+			key := mapKey.(string)
+			checked++
+			if val.HasExpiryDate || len(val.FieldExpireAt) > 0 {
+				sampled++
+			}
+			if val.HasExpiryDate && now.After(val.ExpireAt) {
+				db.DelValue(key)
+				expiredNow++
+			} else if db.expireFields(key, val, now) > 0 {
+				// a hash counts as expired when any of its fields was
+				expiredNow++
+			}
+			if sampled >= activeExpireKeysPerLoop || checked >= activeExpireMaxChecked {
+				break
+			}
+		}
+		expired += expiredNow
+
+		if sampled == 0 || expiredNow*100/sampled <= activeExpireAcceptableStale {
+			return expired
+		}
+		if time.Since(start) > timeLimit {
+			return expired
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveExpireCycle(t *testing.T) {
+	db := NewDb()
+	for i := 0; i < 1000; i++ {
+		db.SetEntry("expired:"+strconv.Itoa(i), &MapValue{
+			Value:         "x",
+			HasExpiryDate: true,
+			ExpireAt:      time.Now().Add(-time.Second),
+		})
+	}
+	for i := 0; i < 10; i++ {
+		db.SetEntry("live:"+strconv.Itoa(i), &MapValue{
+			Value:         "x",
+			HasExpiryDate: true,
+			ExpireAt:      time.Now().Add(time.Hour),
+		})
+	}
+	db.SetValue("persistent", "x")
+
+	expired := db.ActiveExpireCycle(time.Second)
+	assert.Equal(t, 1000, expired)
+	assert.Equal(t, 11, len(db.DbMap))
+}
+
+func TestFieldExpiry(t *testing.T) {
+	db := NewDb()
+	entry := &MapValue{Value: map[string]string{"a": "1", "b": "2"}}
+	db.SetEntry("hash", entry)
+	entry.SetFieldExpiry("a", time.Now().Add(-time.Second))
+	entry.SetFieldExpiry("b", time.Now().Add(time.Hour))
+
+	val, ok := db.GetEntry("hash")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"b": "2"}, val.Value)
+	assert.Equal(t, 1, len(val.FieldExpireAt))
+
+	assert.True(t, entry.PersistField("b"))
+	assert.False(t, entry.PersistField("b"))
+	assert.Equal(t, 0, len(entry.FieldExpireAt))
+
+	// the key goes away with its last field
+	entry.SetFieldExpiry("b", time.Now().Add(-time.Second))
+	_, ok = db.GetEntry("hash")
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(db.DbMap))
+}
+
+func TestActiveExpireCycleFields(t *testing.T) {
+	db := NewDb()
+	for i := 0; i < 100; i++ {
+		entry := &MapValue{Value: map[string]string{"expired": "x", "live": "x"}}
+		db.SetEntry("partial:"+strconv.Itoa(i), entry)
+		entry.SetFieldExpiry("expired", time.Now().Add(-time.Second))
+
+		entry = &MapValue{Value: map[string]string{"expired": "x"}}
+		db.SetEntry("emptied:"+strconv.Itoa(i), entry)
+		entry.SetFieldExpiry("expired", time.Now().Add(-time.Second))
+	}
+
+	expired := db.ActiveExpireCycle(time.Second)
+	assert.Equal(t, 200, expired)
+	assert.Equal(t, 100, len(db.DbMap))
+	for _, val := range db.DbMap {
+		assert.Equal(t, map[string]string{"live": "x"}, val.Value)
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"time"
+)
+
+// SetFieldExpiry makes field of the hash stored in v expire at expireAt.
+// Field expiries are only ever set through here, so nextFieldExpiry stays a
+// lower bound of them.
+func (v *MapValue) SetFieldExpiry(field string, expireAt time.Time) {
+	if v.FieldExpireAt == nil {
+		v.FieldExpireAt = make(map[string]time.Time)
+	}
+	v.FieldExpireAt[field] = expireAt
+	if len(v.FieldExpireAt) == 1 || expireAt.Before(v.nextFieldExpiry) {
+		v.nextFieldExpiry = expireAt
+	}
+}
+
+// PersistField removes the expiry of field, reporting whether it had one.
+func (v *MapValue) PersistField(field string) bool {
+	if _, ok := v.FieldExpireAt[field]; !ok {
+		return false
+	}
+	delete(v.FieldExpireAt, field)
+	return true
+}
+
+// expireFields deletes the fields of the hash at key whose expiry has passed,
+// and key itself once no field is left. It returns the number of fields it
+// deleted.
+func (db *Db) expireFields(key string, val *MapValue, now time.Time) int {
+	// nothing can have expired before the earliest expiry
+	if len(val.FieldExpireAt) == 0 || !now.After(val.nextFieldExpiry) {
+		return 0
+	}
+	hash, _ := val.Value.(map[string]string)
+	expired := 0
+	var next time.Time
+	for field, expireAt := range val.FieldExpireAt {
+		if now.After(expireAt) {
+			delete(hash, field)
+			delete(val.FieldExpireAt, field)
+			expired++
+			continue
+		}
+		if next.IsZero() || expireAt.Before(next) {
+			next = expireAt
+		}
+	}
+	val.nextFieldExpiry = next
+	if len(hash) == 0 {
+		db.DelValue(key)
+	}
+	return expired
+}
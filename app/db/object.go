@@ -0,0 +1,50 @@
+package db
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// The access frequency is a logarithmic counter like the one Redis uses for its
+// LFU eviction policy: new values start at lfuInitVal, each access increments
+// the counter with a probability that shrinks as it grows, and it decays by one
+// for every lfuDecayTime that passes without an access.
+const (
+	lfuInitVal   = 5
+	lfuLogFactor = 10
+	lfuDecayTime = time.Minute
+)
+
+// Touch records an access to the value at now.
+func (v *MapValue) Touch(now time.Time) {
+	counter := v.AccessFrequency(now)
+	if counter < 255 {
+		base := max(float64(counter)-lfuInitVal, 0)
+		if rand.Float64() < 1.0/(base*lfuLogFactor+1) {
+			counter++
+		}
+	}
+	v.Freq = counter
+	v.LastAccess = now
+}
+
+// AccessFrequency returns the access counter after applying the decay for the
+// time elapsed since the last access.
+func (v *MapValue) AccessFrequency(now time.Time) uint8 {
+	periods := int(now.Sub(v.LastAccess) / lfuDecayTime)
+	if v.LastAccess.IsZero() || periods <= 0 {
+		return v.Freq
+	}
+	if periods >= int(v.Freq) {
+		return 0
+	}
+	return v.Freq - uint8(periods)
+}
+
+// IdleTime returns how long ago the value was last accessed.
+func (v *MapValue) IdleTime(now time.Time) time.Duration {
+	if v.LastAccess.IsZero() {
+		return now.Sub(v.SetAt)
+	}
+	return now.Sub(v.LastAccess)
+}
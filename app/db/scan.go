@@ -0,0 +1,121 @@
+package db
+
+import (
+	"cmp"
+	"hash/fnv"
+	"math"
+	"slices"
+)
+
+const (
+	// scanMinBits is the log2 of the smallest number of buckets in a scanIndex.
+	scanMinBits = 4
+	// scanEmptyVisits bounds how many buckets a Scan call visits per key it was
+	// asked for, like Redis' SCAN, so a sparse index cannot stall the loop.
+	scanEmptyVisits = 10
+)
+
+// ScanHash gives every key a fixed position in the SCAN iteration order. Since
+// the order only depends on the key itself, a key that exists for the whole
+// scan is returned exactly once no matter how the keyspace changes between
+// calls. It never returns 0 so that cursor 0 means both "start" and "done".
+func ScanHash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return max(h.Sum64(), 1)
+}
+
+type scanEntry struct {
+	hash uint64
+	key  string
+}
+
+// scanIndex orders the keys of a Db by ScanHash. Keys are bucketed by the top
+// bits of their hash, so a bucket covers a contiguous range of hashes whatever
+// the number of buckets, and a Scan only looks at the buckets it returns keys
+// from instead of the whole keyspace.
+type scanIndex struct {
+	buckets [][]scanEntry
+	bits    uint
+	size    int
+}
+
+func newScanIndex() *scanIndex {
+	return &scanIndex{buckets: make([][]scanEntry, 1<<scanMinBits), bits: scanMinBits}
+}
+
+func (s *scanIndex) bucket(hash uint64) int {
+	return int(hash >> (64 - s.bits))
+}
+
+func (s *scanIndex) add(key string) {
+	hash := ScanHash(key)
+	b := s.bucket(hash)
+	if slices.Contains(s.buckets[b], scanEntry{hash, key}) {
+		return
+	}
+	s.buckets[b] = append(s.buckets[b], scanEntry{hash, key})
+	s.size++
+	if s.size > len(s.buckets) {
+		s.resize(s.bits + 1)
+	}
+}
+
+func (s *scanIndex) remove(key string) {
+	hash := ScanHash(key)
+	b := s.bucket(hash)
+	i := slices.Index(s.buckets[b], scanEntry{hash, key})
+	if i < 0 {
+		return
+	}
+	s.buckets[b] = slices.Delete(s.buckets[b], i, i+1)
+	s.size--
+	if s.bits > scanMinBits && s.size < len(s.buckets)/8 {
+		s.resize(s.bits - 1)
+	}
+}
+
+func (s *scanIndex) resize(bits uint) {
+	old := s.buckets
+	s.buckets, s.bits = make([][]scanEntry, 1<<bits), bits
+	for _, bucket := range old {
+		for _, entry := range bucket {
+			b := s.bucket(entry.hash)
+			s.buckets[b] = append(s.buckets[b], entry)
+		}
+	}
+}
+
+// Scan returns about count keys whose ScanHash is at least cursor, in hash
+// order, and the cursor to continue from, which is 0 once every key was
+// returned. Whole buckets are returned, so keys sharing a hash always come
+// back in the same call.
+func (db *Db) Scan(cursor uint64, count int) ([]string, uint64) {
+	s := db.scan
+	// keep count*scanEmptyVisits from overflowing for a huge COUNT
+	count = min(count, math.MaxInt/scanEmptyVisits)
+	var batch []scanEntry
+	b := s.bucket(cursor)
+	for visits := 0; b < len(s.buckets) && len(batch) < count && visits < count*scanEmptyVisits; visits++ {
+		start := len(batch)
+		for _, entry := range s.buckets[b] {
+			if entry.hash >= cursor {
+				batch = append(batch, entry)
+			}
+		}
+		slices.SortFunc(batch[start:], func(a, b scanEntry) int {
+			return cmp.Compare(a.hash, b.hash)
+		})
+		b++
+	}
+
+	keys := make([]string, len(batch))
+	for i, entry := range batch {
+		keys[i] = entry.key
+	}
+	if b == len(s.buckets) {
+		return keys, 0
+	}
+	// the next bucket starts above every hash returned so far
+	return keys, uint64(b) << (64 - s.bits)
+}
@@ -2,6 +2,7 @@ package eventloop
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/commands"
 )
@@ -9,48 +10,55 @@ import (
 type EventLoop struct {
 	Tasks     chan commands.Command
 	Callbacks chan commands.Command
-	stop      chan bool
+	// Jobs are internal tasks, like the active expire cycle, that have to run
+	// on the loop so they never race with command execution
+	Jobs chan func()
+	stop chan bool
 }
 
 func NewEventLoop() *EventLoop {
 	return &EventLoop{
 		Tasks:     make(chan commands.Command),
 		Callbacks: make(chan commands.Command),
+		Jobs:      make(chan func()),
 	}
 }
 
+// Every queues job on the event loop once per interval.
+func (e *EventLoop) Every(interval time.Duration, job func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			e.Jobs <- job
+		}
+	}()
+}
+
 func (e *EventLoop) Run() {
 	for {
 		select {
 		case task := <-e.Tasks:
 			if task.IsBlocking() {
-				go func() {
-					output, err := task.ExecuteCommand()
-					resultChan := task.GetResponseChan()
-					if err != nil {
-						serializedError := commands.SerializeOutput(task.GetName(), err, true)
-						resultChan <- serializedError
-						return
-					}
-					if output == nil && task.Callback() != nil {
+				// the command runs on the loop so it can be served right away or
+				// queue behind earlier clients; only the waiting happens off it
+				output, err := task.ExecuteCommand()
+				if err == nil && output == nil && task.Callback() != nil {
+					go func() {
+						task.Block()
 						e.Callbacks <- task.Callback()
-						return
-					}
-					outputSerialized := commands.SerializeOutput(task.GetName(), output, false)
-					if outputSerialized == nil {
-						serializedError := commands.SerializeOutput("", fmt.Errorf("unsupported protocol type"), true)
-						resultChan <- serializedError
-						return
-					}
-					resultChan <- outputSerialized
-
-				}()
+					}()
+					continue
+				}
+				reply(task, output, err)
 			} else {
 				handleTask(task)
 			}
 
 		case task := <-e.Callbacks:
 			handleTask(task)
+		case job := <-e.Jobs:
+			job()
 		case stop := <-e.stop:
 			if stop {
 				return
@@ -62,6 +70,10 @@ func (e *EventLoop) Run() {
 
 func handleTask(task commands.Command) {
 	output, err := task.ExecuteCommand()
+	reply(task, output, err)
+}
+
+func reply(task commands.Command, output any, err error) {
 	resultChan := task.GetResponseChan()
 	if err != nil {
 		serializedError := commands.SerializeOutput(task.GetName(), err, true)
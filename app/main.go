@@ -2,11 +2,13 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/commands"
 	"github.com/codecrafters-io/redis-starter-go/app/db"
@@ -18,23 +20,121 @@ var _ = net.Listen
 var _ = os.Exit
 
 var SupportedCommands = map[string]bool{
-	"ECHO":   true,
-	"PING":   true,
-	"SET":    true,
-	"GET":    true,
-	"RPUSH":  true,
-	"LRANGE": true,
-	"LPUSH":  true,
-	"LLEN":   true,
-	"LPOP":   true,
-	"BLPOP":  true,
-	"TYPE":   true,
+	"ECHO":         true,
+	"PING":         true,
+	"SET":          true,
+	"GET":          true,
+	"RPUSH":        true,
+	"LRANGE":       true,
+	"LPUSH":        true,
+	"RPUSHX":       true,
+	"LPUSHX":       true,
+	"LLEN":         true,
+	"LPOP":         true,
+	"BLPOP":        true,
+	"BRPOP":        true,
+	"RPOP":         true,
+	"LINDEX":       true,
+	"LSET":         true,
+	"LINSERT":      true,
+	"LREM":         true,
+	"LTRIM":        true,
+	"LPOS":         true,
+	"LMPOP":        true,
+	"BLMPOP":       true,
+	"LMOVE":        true,
+	"RPOPLPUSH":    true,
+	"BLMOVE":       true,
+	"BRPOPLPUSH":   true,
+	"TYPE":         true,
+	"INCR":         true,
+	"DECR":         true,
+	"INCRBY":       true,
+	"DECRBY":       true,
+	"INCRBYFLOAT":  true,
+	"APPEND":       true,
+	"STRLEN":       true,
+	"GETRANGE":     true,
+	"SETRANGE":     true,
+	"MGET":         true,
+	"MSET":         true,
+	"MSETNX":       true,
+	"GETDEL":       true,
+	"GETSET":       true,
+	"GETEX":        true,
+	"SETBIT":       true,
+	"GETBIT":       true,
+	"BITCOUNT":     true,
+	"BITPOS":       true,
+	"BITOP":        true,
+	"BITFIELD":     true,
+	"LCS":          true,
+	"DEL":          true,
+	"UNLINK":       true,
+	"EXISTS":       true,
+	"EXPIRE":       true,
+	"PEXPIRE":      true,
+	"EXPIREAT":     true,
+	"PEXPIREAT":    true,
+	"TTL":          true,
+	"PTTL":         true,
+	"EXPIRETIME":   true,
+	"PEXPIRETIME":  true,
+	"PERSIST":      true,
+	"KEYS":         true,
+	"SCAN":         true,
+	"RENAME":       true,
+	"RENAMENX":     true,
+	"COPY":         true,
+	"OBJECT":       true,
+	"TOUCH":        true,
+	"DUMP":         true,
+	"RESTORE":      true,
+	"SELECT":       true,
+	"MOVE":         true,
+	"SWAPDB":       true,
+	"FLUSHDB":      true,
+	"FLUSHALL":     true,
+	"DBSIZE":       true,
+	"HSET":         true,
+	"HSETNX":       true,
+	"HGET":         true,
+	"HMGET":        true,
+	"HDEL":         true,
+	"HLEN":         true,
+	"HEXISTS":      true,
+	"HSTRLEN":      true,
+	"HGETALL":      true,
+	"HKEYS":        true,
+	"HVALS":        true,
+	"HINCRBY":      true,
+	"HINCRBYFLOAT": true,
+	"HEXPIRE":      true,
+	"HPEXPIRE":     true,
+	"HEXPIREAT":    true,
+	"HPEXPIREAT":   true,
+	"HTTL":         true,
+	"HPTTL":        true,
+	"HEXPIRETIME":  true,
+	"HPEXPIRETIME": true,
+	"HPERSIST":     true,
+	"HGETEX":       true,
+	"HSETEX":       true,
+	"SORT":         true,
+	"SORT_RO":      true,
 }
 
 func main() {
+	hz := flag.Int("hz", 10, "how many times per second background tasks such as active expiry run")
+	databaseCount := flag.Int("databases", 16, "number of logical databases clients can SELECT")
+	flag.Parse()
+	// same bounds Redis applies to its hz setting
+	*hz = min(max(*hz, 1), 500)
+	*databaseCount = max(*databaseCount, 1)
+
 	fmt.Println("Logs from your program will appear here!")
 
-	db := db.NewDb()
+	databases := db.NewDatabases(*databaseCount)
 	l, err := net.Listen("tcp", "0.0.0.0:6379")
 	if err != nil {
 		fmt.Println("Failed to bind to port 6379")
@@ -44,6 +144,12 @@ func main() {
 	eventLoop := eventloop.NewEventLoop()
 	go eventLoop.Run()
 
+	// like Redis, the active expire cycle may use up to 25% of each period
+	period := time.Second / time.Duration(*hz)
+	eventLoop.Every(period, func() {
+		databases.ActiveExpireCycle(period / 4)
+	})
+
 	for {
 		conn, err := l.Accept()
 		if err != nil {
@@ -51,12 +157,13 @@ func main() {
 			continue
 		}
 
-		go handleConnection(conn, db, eventLoop)
+		go handleConnection(conn, databases, eventLoop)
 	}
 }
 
-func handleConnection(conn net.Conn, db *db.Db, queue *eventloop.EventLoop) {
+func handleConnection(conn net.Conn, databases *db.Databases, queue *eventloop.EventLoop) {
 	defer conn.Close()
+	client := commands.NewClient(databases)
 
 	fmt.Println("Handling Connection", conn.RemoteAddr())
 	reader := bufio.NewReader(conn)
@@ -74,7 +181,7 @@ func handleConnection(conn net.Conn, db *db.Db, queue *eventloop.EventLoop) {
 			continue
 		}
 
-		command, err := RunCommand(value, db, queue)
+		command, err := RunCommand(value, client, queue)
 		if err != nil {
 			serializedError := commands.SerializeOutput("", err, true)
 			conn.Write(serializedError)
@@ -88,7 +195,7 @@ func handleConnection(conn net.Conn, db *db.Db, queue *eventloop.EventLoop) {
 
 }
 
-func RunCommand(input any, db *db.Db, queue *eventloop.EventLoop) (commands.Command, error) {
+func RunCommand(input any, client *commands.Client, queue *eventloop.EventLoop) (commands.Command, error) {
 	arrAsAny, ok := input.([]any)
 	if !ok || len(arrAsAny) == 0 {
 		return nil, fmt.Errorf("command must be an array of strings")
@@ -102,10 +209,11 @@ func RunCommand(input any, db *db.Db, queue *eventloop.EventLoop) (commands.Comm
 	commandName := arr[0]
 	commandName = strings.ToUpper(commandName)
 
-	command, err := commands.NewCommand(commandName, db, arr)
+	command, err := commands.NewCommand(commandName, client.Db(), arr)
 	if err != nil {
 		return nil, err
 	}
+	command.SetClient(client)
 	queue.Tasks <- command
 	return command, nil
 
@@ -98,3 +98,15 @@ func TestBLPOPCommand(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{key, "strawberry"}, val)
 }
+
+func TestFlushAllCommand(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "flush:a", "1", 0).Err())
+	require.NoError(t, client.Set(ctx, "flush:b", "2", 0).Err())
+
+	require.NoError(t, client.FlushAllAsync(ctx).Err())
+
+	size, err := client.DBSize(ctx).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+}
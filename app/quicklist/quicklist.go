@@ -0,0 +1,308 @@
+// Package quicklist implements the list value type: a doubly linked list of
+// fixed size nodes, like the Redis quicklist, giving O(1) pushes and pops at
+// both ends while releasing memory as elements are popped.
+package quicklist
+
+import "iter"
+
+// nodeSize is how many elements a node holds.
+const nodeSize = 64
+
+type node struct {
+	prev, next *node
+	// the node's elements are entries[start:end]
+	entries    [nodeSize]string
+	start, end int
+}
+
+func (n *node) len() int {
+	return n.end - n.start
+}
+
+type List struct {
+	head, tail *node
+	length     int
+}
+
+// New returns a list holding elements, head first.
+func New(elements ...string) *List {
+	l := &List{}
+	for _, element := range elements {
+		l.PushBack(element)
+	}
+	return l
+}
+
+func (l *List) Len() int {
+	return l.length
+}
+
+func (l *List) PushFront(element string) {
+	if l.head == nil || l.head.start == 0 {
+		// a node added at the front fills up from its end
+		n := &node{start: nodeSize, end: nodeSize}
+		l.linkBefore(n, l.head)
+	}
+	l.head.start--
+	l.head.entries[l.head.start] = element
+	l.length++
+}
+
+func (l *List) PushBack(element string) {
+	if l.tail == nil || l.tail.end == nodeSize {
+		l.linkAfter(&node{}, l.tail)
+	}
+	l.tail.entries[l.tail.end] = element
+	l.tail.end++
+	l.length++
+}
+
+func (l *List) PopFront() (string, bool) {
+	if l.length == 0 {
+		return "", false
+	}
+	n := l.head
+	element := n.entries[n.start]
+	n.entries[n.start] = ""
+	n.start++
+	l.length--
+	if n.len() == 0 {
+		l.unlink(n)
+	}
+	return element, true
+}
+
+func (l *List) PopBack() (string, bool) {
+	if l.length == 0 {
+		return "", false
+	}
+	n := l.tail
+	n.end--
+	element := n.entries[n.end]
+	n.entries[n.end] = ""
+	l.length--
+	if n.len() == 0 {
+		l.unlink(n)
+	}
+	return element, true
+}
+
+// Index returns the element at index, counted from the head.
+func (l *List) Index(index int) (string, bool) {
+	n, offset, ok := l.locate(index)
+	if !ok {
+		return "", false
+	}
+	return n.entries[n.start+offset], true
+}
+
+// Set replaces the element at index, reporting whether index was in range.
+func (l *List) Set(index int, element string) bool {
+	n, offset, ok := l.locate(index)
+	if !ok {
+		return false
+	}
+	n.entries[n.start+offset] = element
+	return true
+}
+
+// Insert adds element before the one at index, or at the tail when index is
+// the length of the list.
+func (l *List) Insert(index int, element string) {
+	switch {
+	case index <= 0:
+		l.PushFront(element)
+		return
+	case index >= l.length:
+		l.PushBack(element)
+		return
+	}
+
+	n, offset, _ := l.locate(index)
+	if n.end == nodeSize && n.start == 0 {
+		// split a full node in two halves and insert into the matching one
+		half := &node{}
+		half.end = copy(half.entries[:], n.entries[nodeSize/2:])
+		clear(n.entries[nodeSize/2:])
+		n.end = nodeSize / 2
+		l.linkAfter(half, n)
+		if offset >= n.len() {
+			offset -= n.len()
+			n = half
+		}
+	}
+
+	if n.end < nodeSize {
+		position := n.start + offset
+		copy(n.entries[position+1:n.end+1], n.entries[position:n.end])
+		n.entries[position] = element
+		n.end++
+	} else {
+		position := n.start + offset
+		copy(n.entries[n.start-1:position-1], n.entries[n.start:position])
+		n.start--
+		n.entries[position-1] = element
+	}
+	l.length++
+}
+
+// Remove deletes the element at index, reporting whether index was in range.
+func (l *List) Remove(index int) bool {
+	n, offset, ok := l.locate(index)
+	if !ok {
+		return false
+	}
+	position := n.start + offset
+	copy(n.entries[position:n.end-1], n.entries[position+1:n.end])
+	n.end--
+	n.entries[n.end] = ""
+	l.length--
+	if n.len() == 0 {
+		l.unlink(n)
+	}
+	return true
+}
+
+// Trim keeps only the elements from start to stop, both inclusive and in range.
+func (l *List) Trim(start int, stop int) {
+	for range l.length - stop - 1 {
+		l.PopBack()
+	}
+	for range start {
+		l.PopFront()
+	}
+}
+
+// All yields every element with its index, head first.
+func (l *List) All() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		index := 0
+		for n := l.head; n != nil; n = n.next {
+			for _, element := range n.entries[n.start:n.end] {
+				if !yield(index, element) {
+					return
+				}
+				index++
+			}
+		}
+	}
+}
+
+// Backward yields every element with its index, tail first.
+func (l *List) Backward() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		index := l.length - 1
+		for n := l.tail; n != nil; n = n.prev {
+			for i := n.end - 1; i >= n.start; i-- {
+				if !yield(index, n.entries[i]) {
+					return
+				}
+				index--
+			}
+		}
+	}
+}
+
+// Range returns a copy of the elements from start to stop, both inclusive and
+// in range.
+func (l *List) Range(start int, stop int) []string {
+	result := make([]string, 0, stop-start+1)
+	n, offset, ok := l.locate(start)
+	for ok && len(result) < cap(result) {
+		take := min(n.len()-offset, cap(result)-len(result))
+		result = append(result, n.entries[n.start+offset:n.start+offset+take]...)
+		n, offset = n.next, 0
+		ok = n != nil
+	}
+	return result
+}
+
+// Slice returns a copy of every element, head first.
+func (l *List) Slice() []string {
+	if l.length == 0 {
+		return []string{}
+	}
+	return l.Range(0, l.length-1)
+}
+
+func (l *List) Clone() *List {
+	clone := &List{length: l.length}
+	for n := l.head; n != nil; n = n.next {
+		copied := *n
+		copied.prev, copied.next = clone.tail, nil
+		clone.linkAfter(&copied, clone.tail)
+	}
+	return clone
+}
+
+// locate finds the node holding index and the element's offset within it,
+// walking from whichever end is closer.
+func (l *List) locate(index int) (*node, int, bool) {
+	if index < 0 || index >= l.length {
+		return nil, 0, false
+	}
+	if index < l.length/2 {
+		for n := l.head; n != nil; n = n.next {
+			if index < n.len() {
+				return n, index, true
+			}
+			index -= n.len()
+		}
+	}
+	index = l.length - 1 - index
+	for n := l.tail; n != nil; n = n.prev {
+		if index < n.len() {
+			return n, n.len() - 1 - index, true
+		}
+		index -= n.len()
+	}
+	return nil, 0, false
+}
+
+func (l *List) linkBefore(n *node, next *node) {
+	if next == nil {
+		l.linkAfter(n, l.tail)
+		return
+	}
+	n.prev, n.next = next.prev, next
+	if next.prev != nil {
+		next.prev.next = n
+	} else {
+		l.head = n
+	}
+	next.prev = n
+}
+
+func (l *List) linkAfter(n *node, prev *node) {
+	if prev == nil {
+		n.prev, n.next = nil, l.head
+		if l.head != nil {
+			l.head.prev = n
+		} else {
+			l.tail = n
+		}
+		l.head = n
+		return
+	}
+	n.prev, n.next = prev, prev.next
+	if prev.next != nil {
+		prev.next.prev = n
+	} else {
+		l.tail = n
+	}
+	prev.next = n
+}
+
+func (l *List) unlink(n *node) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		l.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		l.tail = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
@@ -0,0 +1,94 @@
+package quicklist
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushAndPop(t *testing.T) {
+	l := New()
+	var expected []string
+	for i := range 3 * nodeSize {
+		element := strconv.Itoa(i)
+		if i%2 == 0 {
+			l.PushFront(element)
+			expected = append([]string{element}, expected...)
+		} else {
+			l.PushBack(element)
+			expected = append(expected, element)
+		}
+	}
+	assert.Equal(t, len(expected), l.Len())
+	assert.Equal(t, expected, l.Slice())
+
+	for len(expected) > 0 {
+		front, ok := l.PopFront()
+		assert.True(t, ok)
+		assert.Equal(t, expected[0], front)
+		expected = expected[1:]
+		if len(expected) == 0 {
+			break
+		}
+		back, ok := l.PopBack()
+		assert.True(t, ok)
+		assert.Equal(t, expected[len(expected)-1], back)
+		expected = expected[:len(expected)-1]
+	}
+	_, ok := l.PopFront()
+	assert.False(t, ok)
+	assert.Nil(t, l.head)
+	assert.Nil(t, l.tail)
+}
+
+func TestMiddleOperations(t *testing.T) {
+	l := New()
+	var expected []string
+	for i := range 2 * nodeSize {
+		l.PushBack(strconv.Itoa(i))
+		expected = append(expected, strconv.Itoa(i))
+	}
+
+	// inserts into full nodes split them
+	for _, index := range []int{1, nodeSize, nodeSize + 1, 0, 2*nodeSize + 3, 17} {
+		l.Insert(index, "x"+strconv.Itoa(index))
+		expected = slices.Insert(expected, index, "x"+strconv.Itoa(index))
+		assert.Equal(t, expected, l.Slice())
+	}
+
+	for _, index := range []int{5, nodeSize, 0, -1} {
+		if index < 0 {
+			index = len(expected) - 1
+		}
+		assert.True(t, l.Remove(index))
+		expected = slices.Delete(expected, index, index+1)
+		assert.Equal(t, expected, l.Slice())
+	}
+	assert.False(t, l.Remove(len(expected)))
+
+	assert.True(t, l.Set(70, "set"))
+	expected[70] = "set"
+	element, ok := l.Index(70)
+	assert.True(t, ok)
+	assert.Equal(t, "set", element)
+	_, ok = l.Index(-1)
+	assert.False(t, ok)
+
+	assert.Equal(t, expected[3:80], l.Range(3, 79))
+	backward := []string{}
+	for i, element := range l.Backward() {
+		assert.Equal(t, expected[i], element)
+		backward = append(backward, element)
+	}
+	slices.Reverse(backward)
+	assert.Equal(t, expected, backward)
+
+	clone := l.Clone()
+	l.Trim(10, 20)
+	assert.Equal(t, expected[10:21], l.Slice())
+	assert.Equal(t, expected, clone.Slice())
+	clone.PushFront("front")
+	assert.Equal(t, expected[10:21], l.Slice())
+}
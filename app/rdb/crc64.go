@@ -0,0 +1,33 @@
+package rdb
+
+// Redis checksums DUMP payloads with the Jones CRC-64 variant: polynomial
+// 0xad93d23594c935a9, reflected input and output, zero initial value and no
+// final xor. The standard library's hash/crc64 always inverts the crc, so it
+// is computed here. The table below shifts right, so it takes the polynomial
+// bit-reversed.
+const jonesPolynomial = 0x95ac9329ac4bc9b5
+
+var crcTable = makeCRCTable()
+
+func makeCRCTable() [256]uint64 {
+	var table [256]uint64
+	for i := range table {
+		crc := uint64(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 == 1 {
+				crc = crc>>1 ^ jonesPolynomial
+			} else {
+				crc >>= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}
+
+func crc64(crc uint64, data []byte) uint64 {
+	for _, b := range data {
+		crc = crcTable[byte(crc)^b] ^ crc>>8
+	}
+	return crc
+}
@@ -0,0 +1,404 @@
+// Package rdb encodes and decodes single values in the payload format used by
+// Redis' DUMP and RESTORE commands.
+//
+// A payload is the RDB serialization of one value: a type byte and the object,
+// followed by a 2 byte little endian RDB version and an 8 byte little endian
+// CRC-64 of everything before it.
+package rdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+)
+
+// RDB object types. Values are always dumped with the plain, version
+// independent types, except hashes with field expiries which only exist since
+// RDB 12; the compact listpack based types written by recent Redis versions are
+// understood when restoring.
+const (
+	typeString          = 0
+	typeList            = 1
+	typeHash            = 4
+	typeHashListpack    = 16
+	typeListQuicklist2  = 18
+	typeHashMetadata    = 24
+	quicklistNodePlain  = 1
+	quicklistNodePacked = 2
+)
+
+// Special string encodings, flagged by the two top bits of the length being 11.
+const (
+	encodingInt8  = 0
+	encodingInt16 = 1
+	encodingInt32 = 2
+	encodingLZF   = 3
+)
+
+const (
+	// dumpVersion is written to payloads. 9 is understood by Redis 6 and later,
+	// which is all that is needed for the object types we emit.
+	dumpVersion = 9
+	// maxVersion is the newest RDB version accepted by Restore (Redis 7.4), and
+	// the one written for hashes with field expiries.
+	maxVersion = 12
+	// maxStringLength bounds the size of a decompressed string, like Redis'
+	// proto-max-bulk-len default.
+	maxStringLength = 512 * 1024 * 1024
+)
+
+var (
+	ErrBadPayload      = errors.New("DUMP payload version or checksum are wrong")
+	ErrBadFormat       = errors.New("Bad data format")
+	ErrUnsupportedType = errors.New("unsupported value type")
+	errTruncated       = errors.New("truncated payload")
+)
+
+// ExpiringHash is a hash some of whose fields expire, at the given unix times in
+// milliseconds. Dump takes and Restore returns hashes without field expiries as
+// a plain map[string]string.
+type ExpiringHash struct {
+	Fields   map[string]string
+	ExpireAt map[string]int64
+}
+
+// Dump serializes value into a DUMP payload.
+func Dump(value any) ([]byte, error) {
+	var buf []byte
+	version := uint16(dumpVersion)
+	switch v := value.(type) {
+	case string:
+		buf = append(buf, typeString)
+		buf = appendString(buf, v)
+	case *quicklist.List:
+		buf = append(buf, typeList)
+		buf = appendLength(buf, uint64(v.Len()))
+		for _, elem := range v.All() {
+			buf = appendString(buf, elem)
+		}
+	case map[string]string:
+		buf = append(buf, typeHash)
+		buf = appendLength(buf, uint64(len(v)))
+		for field, value := range v {
+			buf = appendString(buf, field)
+			buf = appendString(buf, value)
+		}
+	case ExpiringHash:
+		// every field's expiry is stored relative to the earliest one, plus one
+		// so that 0 can stand for no expiry
+		minExpire := int64(math.MaxInt64)
+		for _, expireAt := range v.ExpireAt {
+			minExpire = min(minExpire, expireAt)
+		}
+		buf = append(buf, typeHashMetadata)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(minExpire))
+		buf = appendLength(buf, uint64(len(v.Fields)))
+		for field, value := range v.Fields {
+			var ttl uint64
+			if expireAt, ok := v.ExpireAt[field]; ok {
+				ttl = uint64(expireAt-minExpire) + 1
+			}
+			buf = appendLength(buf, ttl)
+			buf = appendString(buf, field)
+			buf = appendString(buf, value)
+		}
+		version = maxVersion
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	buf = binary.LittleEndian.AppendUint16(buf, version)
+	return binary.LittleEndian.AppendUint64(buf, crc64(0, buf)), nil
+}
+
+// Restore parses a DUMP payload back into a value, after checking its version
+// and checksum.
+func Restore(payload []byte) (any, error) {
+	if len(payload) < 10 {
+		return nil, ErrBadPayload
+	}
+	footer := len(payload) - 10
+	version := binary.LittleEndian.Uint16(payload[footer:])
+	checksum := binary.LittleEndian.Uint64(payload[footer+2:])
+	if version > maxVersion || crc64(0, payload[:footer+2]) != checksum {
+		return nil, ErrBadPayload
+	}
+
+	r := &reader{buf: payload[:footer]}
+	value, err := r.readObject()
+	if err != nil || r.pos != len(r.buf) {
+		return nil, ErrBadFormat
+	}
+	return value, nil
+}
+
+func appendLength(buf []byte, length uint64) []byte {
+	switch {
+	case length < 1<<6:
+		return append(buf, byte(length))
+	case length < 1<<14:
+		return append(buf, byte(length>>8)|0x40, byte(length))
+	case length <= math.MaxUint32:
+		buf = append(buf, 0x80)
+		return binary.BigEndian.AppendUint32(buf, uint32(length))
+	default:
+		buf = append(buf, 0x81)
+		return binary.BigEndian.AppendUint64(buf, length)
+	}
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendLength(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) next(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.buf) {
+		return nil, errTruncated
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// readLength reads a length prefix. encoded is true when the prefix instead
+// names one of the special string encodings, which is then returned as length.
+func (r *reader) readLength() (length uint64, encoded bool, err error) {
+	b, err := r.next(1)
+	if err != nil {
+		return 0, false, err
+	}
+	switch b[0] >> 6 {
+	case 0:
+		return uint64(b[0] & 0x3f), false, nil
+	case 1:
+		low, err := r.next(1)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(b[0]&0x3f)<<8 | uint64(low[0]), false, nil
+	case 2:
+		switch b[0] {
+		case 0x80:
+			raw, err := r.next(4)
+			if err != nil {
+				return 0, false, err
+			}
+			return uint64(binary.BigEndian.Uint32(raw)), false, nil
+		case 0x81:
+			raw, err := r.next(8)
+			if err != nil {
+				return 0, false, err
+			}
+			return binary.BigEndian.Uint64(raw), false, nil
+		}
+		return 0, false, fmt.Errorf("unknown length encoding %x", b[0])
+	default:
+		return uint64(b[0] & 0x3f), true, nil
+	}
+}
+
+func (r *reader) readCount() (int, error) {
+	length, encoded, err := r.readLength()
+	if err != nil {
+		return 0, err
+	}
+	if encoded || length > uint64(len(r.buf)) {
+		return 0, errTruncated
+	}
+	return int(length), nil
+}
+
+func (r *reader) readString() (string, error) {
+	length, encoded, err := r.readLength()
+	if err != nil {
+		return "", err
+	}
+	if !encoded {
+		if length > uint64(len(r.buf)) {
+			return "", errTruncated
+		}
+		raw, err := r.next(int(length))
+		return string(raw), err
+	}
+
+	switch length {
+	case encodingInt8:
+		raw, err := r.next(1)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int8(raw[0]))), nil
+	case encodingInt16:
+		raw, err := r.next(2)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int16(binary.LittleEndian.Uint16(raw)))), nil
+	case encodingInt32:
+		raw, err := r.next(4)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int32(binary.LittleEndian.Uint32(raw)))), nil
+	case encodingLZF:
+		compressedLength, err := r.readCount()
+		if err != nil {
+			return "", err
+		}
+		length, encoded, err := r.readLength()
+		if err != nil {
+			return "", err
+		}
+		if encoded || length > maxStringLength {
+			return "", errTruncated
+		}
+		compressed, err := r.next(compressedLength)
+		if err != nil {
+			return "", err
+		}
+		raw, err := lzfDecompress(compressed, int(length))
+		return string(raw), err
+	default:
+		return "", fmt.Errorf("unknown string encoding %d", length)
+	}
+}
+
+func (r *reader) readObject() (any, error) {
+	objectType, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch objectType[0] {
+	case typeString:
+		return r.readString()
+	case typeList:
+		count, err := r.readCount()
+		if err != nil {
+			return nil, err
+		}
+		list := quicklist.New()
+		for i := 0; i < count; i++ {
+			elem, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			list.PushBack(elem)
+		}
+		return list, nil
+	case typeHash:
+		count, err := r.readCount()
+		if err != nil {
+			return nil, err
+		}
+		hash := make(map[string]string, count)
+		for i := 0; i < count; i++ {
+			field, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			value, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			hash[field] = value
+		}
+		return hash, nil
+	case typeHashListpack:
+		packed, err := r.readString()
+		if err != nil {
+			return nil, err
+		}
+		entries, err := decodeListpack([]byte(packed))
+		if err != nil {
+			return nil, err
+		}
+		if len(entries)%2 != 0 {
+			return nil, ErrBadFormat
+		}
+		hash := make(map[string]string, len(entries)/2)
+		for i := 0; i < len(entries); i += 2 {
+			hash[entries[i]] = entries[i+1]
+		}
+		return hash, nil
+	case typeHashMetadata:
+		raw, err := r.next(8)
+		if err != nil {
+			return nil, err
+		}
+		minExpire := int64(binary.LittleEndian.Uint64(raw))
+		count, err := r.readCount()
+		if err != nil {
+			return nil, err
+		}
+		hash := ExpiringHash{
+			Fields:   make(map[string]string, count),
+			ExpireAt: make(map[string]int64),
+		}
+		for i := 0; i < count; i++ {
+			ttl, encoded, err := r.readLength()
+			if err != nil {
+				return nil, err
+			}
+			if encoded {
+				return nil, ErrBadFormat
+			}
+			field, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			value, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			hash.Fields[field] = value
+			if ttl != 0 {
+				hash.ExpireAt[field] = minExpire + int64(ttl) - 1
+			}
+		}
+		return hash, nil
+	case typeListQuicklist2:
+		nodes, err := r.readCount()
+		if err != nil {
+			return nil, err
+		}
+		list := quicklist.New()
+		for i := 0; i < nodes; i++ {
+			container, _, err := r.readLength()
+			if err != nil {
+				return nil, err
+			}
+			node, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			switch container {
+			case quicklistNodePlain:
+				list.PushBack(node)
+			case quicklistNodePacked:
+				entries, err := decodeListpack([]byte(node))
+				if err != nil {
+					return nil, err
+				}
+				for _, entry := range entries {
+					list.PushBack(entry)
+				}
+			default:
+				return nil, fmt.Errorf("unknown quicklist container %d", container)
+			}
+		}
+		return list, nil
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
@@ -0,0 +1,96 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC64(t *testing.T) {
+	assert.Equal(t, uint64(0xe9c6d914c4b8d9ca), crc64(0, []byte("123456789")))
+}
+
+// withFooter appends the version and checksum to a hand built object.
+func withFooter(object []byte, version uint16) []byte {
+	payload := binary.LittleEndian.AppendUint16(object, version)
+	return binary.LittleEndian.AppendUint64(payload, crc64(0, payload))
+}
+
+func TestRestoreRedisPayloads(t *testing.T) {
+	testCases := []struct {
+		name     string
+		payload  []byte
+		expected any
+	}{
+		{
+			// DUMP of the integer 10 taken from the Redis documentation
+			name:     "int encoded string",
+			payload:  []byte("\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n"),
+			expected: "10",
+		},
+		{
+			name:     "lzf compressed string",
+			payload:  withFooter([]byte("\x00\xc3\x05\x0a\x00\x61\xe0\x00\x00"), 11),
+			expected: "aaaaaaaaaa",
+		},
+		{
+			name: "quicklist with listpack and plain nodes",
+			payload: withFooter([]byte(
+				"\x12\x02"+
+					"\x02\x10"+"\x10\x00\x00\x00\x03\x00"+"\x82ab\x03"+"\x05\x01"+"\xdf\xfd\x02"+"\xff"+
+					"\x01\x05hello"), 11),
+			expected: quicklist.New("ab", "5", "-3", "hello"),
+		},
+		{
+			name: "listpack hash",
+			payload: withFooter([]byte(
+				"\x10\x19"+
+					"\x19\x00\x00\x00\x04\x00"+"\x84name\x05"+"\x83ann\x04"+"\x83age\x04"+"\x1e\x01"+"\xff"), 11),
+			expected: map[string]string{"name": "ann", "age": "30"},
+		},
+	}
+
+	for _, tt := range testCases {
+		value, err := Restore(tt.payload)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, value, tt.name)
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	long := make([]byte, 20000)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	values := []any{"", "hello", string(long), quicklist.New("a", "", string(long)), map[string]string{"a": "", "": string(long)},
+		ExpiringHash{
+			Fields:   map[string]string{"a": "1", "b": "2", "c": "3"},
+			ExpireAt: map[string]int64{"a": 1893456000000, "b": 1893456123456},
+		},
+	}
+
+	for _, value := range values {
+		payload, err := Dump(value)
+		assert.NoError(t, err)
+		restored, err := Restore(payload)
+		assert.NoError(t, err)
+		assert.Equal(t, value, restored)
+	}
+}
+
+func TestRestoreRejectsCorruptPayloads(t *testing.T) {
+	payload, _ := Dump("hello")
+
+	corrupt := append([]byte{}, payload...)
+	corrupt[2] ^= 0xff
+	_, err := Restore(corrupt)
+	assert.Equal(t, ErrBadPayload, err)
+
+	_, err = Restore(withFooter([]byte("\x00\x05hel"), 9))
+	assert.Equal(t, ErrBadFormat, err)
+
+	_, err = Restore(withFooter([]byte("\x00\x05hello"), 99))
+	assert.Equal(t, ErrBadPayload, err)
+}
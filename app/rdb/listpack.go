@@ -0,0 +1,109 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+var errCorruptListpack = errors.New("corrupt listpack")
+
+// decodeListpack returns the entries of a listpack blob, with integer entries
+// converted to their decimal string form.
+func decodeListpack(lp []byte) ([]string, error) {
+	// 4 byte total size and 2 byte element count
+	if len(lp) < 7 || int(binary.LittleEndian.Uint32(lp)) != len(lp) {
+		return nil, errCorruptListpack
+	}
+	entries := []string{}
+	pos := 6
+	for {
+		if pos >= len(lp) {
+			return nil, errCorruptListpack
+		}
+		b := lp[pos]
+		if b == 0xff {
+			break
+		}
+
+		var entry string
+		var size int
+		switch {
+		case b&0x80 == 0:
+			// 7 bit unsigned integer
+			entry, size = strconv.Itoa(int(b&0x7f)), 1
+		case b&0xc0 == 0x80:
+			// string up to 63 bytes
+			length := int(b & 0x3f)
+			if pos+1+length > len(lp) {
+				return nil, errCorruptListpack
+			}
+			entry, size = string(lp[pos+1:pos+1+length]), 1+length
+		case b&0xe0 == 0xc0:
+			// 13 bit signed integer
+			if pos+2 > len(lp) {
+				return nil, errCorruptListpack
+			}
+			value := int(b&0x1f)<<8 | int(lp[pos+1])
+			if value >= 1<<12 {
+				value -= 1 << 13
+			}
+			entry, size = strconv.Itoa(value), 2
+		case b&0xf0 == 0xe0:
+			// string up to 4095 bytes
+			if pos+2 > len(lp) {
+				return nil, errCorruptListpack
+			}
+			length := int(b&0x0f)<<8 | int(lp[pos+1])
+			if pos+2+length > len(lp) {
+				return nil, errCorruptListpack
+			}
+			entry, size = string(lp[pos+2:pos+2+length]), 2+length
+		case b == 0xf0:
+			// string with a 32 bit length
+			if pos+5 > len(lp) {
+				return nil, errCorruptListpack
+			}
+			length := int(binary.LittleEndian.Uint32(lp[pos+1:]))
+			if length < 0 || pos+5+length > len(lp) {
+				return nil, errCorruptListpack
+			}
+			entry, size = string(lp[pos+5:pos+5+length]), 5+length
+		case b >= 0xf1 && b <= 0xf4:
+			// 16, 24, 32 or 64 bit signed integer
+			width := []int{2, 3, 4, 8}[b-0xf1]
+			if pos+1+width > len(lp) {
+				return nil, errCorruptListpack
+			}
+			var raw [8]byte
+			copy(raw[:], lp[pos+1:pos+1+width])
+			value := int64(binary.LittleEndian.Uint64(raw[:]))
+			// sign extend the narrower integers
+			shift := uint(64 - 8*width)
+			value = value << shift >> shift
+			entry, size = strconv.FormatInt(value, 10), 1+width
+		default:
+			return nil, errCorruptListpack
+		}
+
+		entries = append(entries, entry)
+		pos += size + backlenSize(size)
+	}
+	return entries, nil
+}
+
+// backlenSize is the number of bytes used to store an entry's length at its end.
+func backlenSize(size int) int {
+	switch {
+	case size < 1<<7:
+		return 1
+	case size < 1<<14:
+		return 2
+	case size < 1<<21:
+		return 3
+	case size < 1<<28:
+		return 4
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,51 @@
+package rdb
+
+import "errors"
+
+var errCorruptLZF = errors.New("corrupt LZF data")
+
+// lzfDecompress expands LZF compressed data into a buffer of exactly size bytes.
+func lzfDecompress(in []byte, size int) ([]byte, error) {
+	out := make([]byte, 0, size)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+		if ctrl < 32 {
+			// literal run of ctrl+1 bytes
+			length := ctrl + 1
+			if i+length > len(in) || len(out)+length > size {
+				return nil, errCorruptLZF
+			}
+			out = append(out, in[i:i+length]...)
+			i += length
+			continue
+		}
+
+		// back reference into the output produced so far
+		length := ctrl >> 5
+		if length == 7 {
+			if i >= len(in) {
+				return nil, errCorruptLZF
+			}
+			length += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, errCorruptLZF
+		}
+		ref := len(out) - (ctrl&0x1f)<<8 - int(in[i]) - 1
+		i++
+		length += 2
+		if ref < 0 || len(out)+length > size {
+			return nil, errCorruptLZF
+		}
+		// the copy may overlap the bytes it appends, so go one byte at a time
+		for j := 0; j < length; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if len(out) != size {
+		return nil, errCorruptLZF
+	}
+	return out, nil
+}